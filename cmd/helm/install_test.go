@@ -107,6 +107,14 @@ func TestInstall(t *testing.T) {
 			expected: "apollo",
 			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "apollo"}),
 		},
+		// Install, with wait for jobs
+		{
+			name:     "install with a wait for jobs",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    strings.Split("--name apollo --wait --wait-for-jobs", " "),
+			expected: "apollo",
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "apollo"}),
+		},
 		// Install, using the name-template
 		{
 			name:     "install with name-template",
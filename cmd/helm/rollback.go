@@ -17,10 +17,12 @@ limitations under the License.
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
 
+	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/helm"
@@ -32,20 +34,34 @@ This command rolls back a release to a previous revision.
 The first argument of the rollback command is the name of a release, and the
 second is a revision (version) number. To see revision numbers, run
 'helm history RELEASE'.
+
+With '--dry-run', nothing is rolled back. Instead, a unified diff between the
+manifest of the current release and that of the target revision is printed,
+and the rollback is only checked by Tiller.
 `
 
 type rollbackCmd struct {
-	name         string
-	revision     int32
-	dryRun       bool
-	recreate     bool
-	force        bool
-	disableHooks bool
-	out          io.Writer
-	client       helm.Interface
-	timeout      int64
-	wait         bool
-	description  string
+	name          string
+	revision      int32
+	dryRun        bool
+	recreate      bool
+	force         bool
+	disableHooks  bool
+	out           io.Writer
+	client        helm.Interface
+	timeout       int64
+	wait          bool
+	waitForJobs   bool
+	description   string
+	cleanupOnFail bool
+	outputFormat  string
+}
+
+type rollbackResult struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Revision  int32  `json:"revision"`
+	Status    string `json:"status"`
 }
 
 func newRollbackCmd(c helm.Interface, out io.Writer) *cobra.Command {
@@ -78,19 +94,37 @@ func newRollbackCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	}
 
 	f := cmd.Flags()
-	f.BoolVar(&rollback.dryRun, "dry-run", false, "simulate a rollback")
+	f.BoolVar(&rollback.dryRun, "dry-run", false, "simulate a rollback, printing a diff of the manifests instead of applying it")
 	f.BoolVar(&rollback.recreate, "recreate-pods", false, "performs pods restart for the resource if applicable")
 	f.BoolVar(&rollback.force, "force", false, "force resource update through delete/recreate if needed")
 	f.BoolVar(&rollback.disableHooks, "no-hooks", false, "prevent hooks from running during rollback")
 	f.Int64Var(&rollback.timeout, "timeout", 300, "time in seconds to wait for any individual Kubernetes operation (like Jobs for hooks)")
 	f.BoolVar(&rollback.wait, "wait", false, "if set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
+	f.BoolVar(&rollback.waitForJobs, "wait-for-jobs", false, "if set and --wait enabled, will wait until all Jobs have been completed before marking the release as successful. It will wait for as long as --timeout")
 	f.StringVar(&rollback.description, "description", "", "specify a description for the release")
+	f.BoolVar(&rollback.cleanupOnFail, "cleanup-on-fail", false, "allow deletion of new resources created in this rollback when rollback failed")
+	f.StringVarP(&rollback.outputFormat, "output", "o", "table", "prints the output in the specified format (json|table|yaml)")
 
 	return cmd
 }
 
 func (r *rollbackCmd) run() error {
-	_, err := r.client.RollbackRelease(
+	switch r.outputFormat {
+	case "table", "json", "yaml":
+	default:
+		return fmt.Errorf("unknown output format %q", r.outputFormat)
+	}
+
+	if r.dryRun {
+		if r.outputFormat != "table" {
+			return fmt.Errorf("--output %s cannot be used with --dry-run, which prints a diff", r.outputFormat)
+		}
+		if err := r.diff(); err != nil {
+			return err
+		}
+	}
+
+	res, err := r.client.RollbackRelease(
 		r.name,
 		helm.RollbackDryRun(r.dryRun),
 		helm.RollbackRecreate(r.recreate),
@@ -99,12 +133,72 @@ func (r *rollbackCmd) run() error {
 		helm.RollbackVersion(r.revision),
 		helm.RollbackTimeout(r.timeout),
 		helm.RollbackWait(r.wait),
-		helm.RollbackDescription(r.description))
+		helm.RollbackWaitForJobs(r.waitForJobs),
+		helm.RollbackDescription(r.description),
+		helm.RollbackCleanupOnFail(r.cleanupOnFail))
 	if err != nil {
 		return prettyError(err)
 	}
 
-	fmt.Fprintf(r.out, "Rollback was a success! Happy Helming!\n")
+	if r.dryRun {
+		return nil
+	}
+	if r.outputFormat == "table" {
+		fmt.Fprintf(r.out, "Rollback was a success! Happy Helming!\n")
+		return nil
+	}
+
+	rel := res.GetRelease()
+	if rel == nil {
+		return fmt.Errorf("no release returned for rollback of %q", r.name)
+	}
+	result := rollbackResult{
+		Name:      rel.Name,
+		Namespace: rel.Namespace,
+		Revision:  rel.Version,
+		Status:    rel.GetInfo().GetStatus().GetCode().String(),
+	}
+
+	var output []byte
+	if r.outputFormat == "json" {
+		output, err = json.Marshal(result)
+	} else {
+		output, err = yaml.Marshal(result)
+	}
+	if err != nil {
+		return prettyError(err)
+	}
 
+	fmt.Fprintln(r.out, string(output))
+	return nil
+}
+
+// diff prints a unified diff from the manifest of the current release to the
+// manifest of the revision being rolled back to.
+func (r *rollbackCmd) diff() error {
+	current, err := r.client.ReleaseContent(r.name)
+	if err != nil {
+		return prettyError(err)
+	}
+
+	revision := r.revision
+	if revision == 0 {
+		revision = current.Release.Version - 1
+	}
+	target, err := r.client.ReleaseContent(r.name, helm.ContentReleaseVersion(revision))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	diff, err := helm.DiffReleases(current.Release, target.Release)
+	if err != nil {
+		return err
+	}
+
+	if diff == "" {
+		fmt.Fprintf(r.out, "No changes to %s between revisions %d and %d\n", r.name, current.Release.Version, target.Release.Version)
+		return nil
+	}
+	fmt.Fprint(r.out, diff)
 	return nil
 }
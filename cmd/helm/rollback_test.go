@@ -17,12 +17,21 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"io"
+	"io/ioutil"
+	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
 
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	rls "k8s.io/helm/pkg/proto/hapi/services"
 )
 
 func TestRollbackCmd(t *testing.T) {
@@ -45,12 +54,46 @@ func TestRollbackCmd(t *testing.T) {
 			flags:    []string{"--wait"},
 			expected: "Rollback was a success! Happy Helming!",
 		},
+		{
+			name:     "rollback a release with wait for jobs",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--wait", "--wait-for-jobs"},
+			expected: "Rollback was a success! Happy Helming!",
+		},
 		{
 			name:     "rollback a release with description",
 			args:     []string{"funny-honey", "1"},
 			flags:    []string{"--description", "foo"},
 			expected: "Rollback was a success! Happy Helming!",
 		},
+		{
+			name:     "rollback a release without hooks",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--no-hooks"},
+			expected: "Rollback was a success! Happy Helming!",
+		},
+		{
+			name:     "rollback a release with cleanup on fail",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--cleanup-on-fail"},
+			expected: "Rollback was a success! Happy Helming!",
+		},
+		{
+			name:  "rollback a release with yaml output",
+			args:  []string{"funny-honey", "1"},
+			flags: []string{"--output", "yaml"},
+			rels: []*release.Release{
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey", Version: 3}),
+				helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+			},
+			expected: "name: funny-honey\nnamespace: default\nrevision: 3\nstatus: DEPLOYED\n",
+		},
+		{
+			name:  "rollback a release with an unknown output format",
+			args:  []string{"funny-honey", "1"},
+			flags: []string{"--output", "xml"},
+			err:   true,
+		},
 		{
 			name: "rollback a release without revision",
 			args: []string{"funny-honey"},
@@ -65,3 +108,111 @@ func TestRollbackCmd(t *testing.T) {
 	runReleaseCases(t, tests, cmd)
 
 }
+
+func TestRollbackCmdCleanupOnFail(t *testing.T) {
+	errSkip := errors.New("skip the call to tiller")
+	var req *rls.RollbackReleaseRequest
+	c := helm.NewClient(helm.BeforeCall(func(_ context.Context, msg proto.Message) error {
+		req, _ = msg.(*rls.RollbackReleaseRequest)
+		return errSkip
+	}))
+
+	cmd := newRollbackCmd(c, ioutil.Discard)
+	if err := cmd.ParseFlags([]string{"--cleanup-on-fail"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.RunE(cmd, []string{"funny-honey", "1"}); err == nil {
+		t.Fatal("expected the intercepted call to fail")
+	}
+	if req == nil {
+		t.Fatal("expected a RollbackReleaseRequest to be sent")
+	}
+	if !req.CleanupOnFail {
+		t.Error("expected CleanupOnFail to be set on the request")
+	}
+}
+
+func TestRollbackCmdJSONOutput(t *testing.T) {
+	var buf bytes.Buffer
+	c := &helm.FakeClient{
+		Rels: []*release.Release{
+			helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey", Namespace: "tea", Version: 3}),
+			helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey", Namespace: "tea", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+		},
+	}
+
+	cmd := newRollbackCmd(c, &buf)
+	if err := cmd.ParseFlags([]string{"--output", "json"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.RunE(cmd, []string{"funny-honey", "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got rollbackResult
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %s", buf.String(), err)
+	}
+	expected := rollbackResult{Name: "funny-honey", Namespace: "tea", Revision: 3, Status: "DEPLOYED"}
+	if got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestRollbackCmdDryRunDiff(t *testing.T) {
+	mk := func(version int32, manifest string) *release.Release {
+		rel := helm.ReleaseMock(&helm.MockReleaseOptions{Name: "funny-honey", Version: version})
+		rel.Manifest = manifest
+		return rel
+	}
+	var buf bytes.Buffer
+	c := &helm.FakeClient{
+		Rels: []*release.Release{
+			mk(2, "kind: ConfigMap\nmetadata:\n  name: current\n"),
+			mk(1, "kind: ConfigMap\nmetadata:\n  name: previous\n"),
+		},
+	}
+
+	cmd := newRollbackCmd(c, &buf)
+	if err := cmd.ParseFlags([]string{"--dry-run"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.RunE(cmd, []string{"funny-honey", "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"--- ConfigMap/current (revision 2)",
+		"-  name: current",
+		"+++ ConfigMap/previous (revision 1)",
+		"+  name: previous",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected diff to contain %q, got\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Rollback was a success") {
+		t.Errorf("expected dry run not to roll back, got\n%s", out)
+	}
+	if len(c.Rels) != 2 {
+		t.Errorf("expected no new revision, got %d revisions", len(c.Rels))
+	}
+	var req *rls.RollbackReleaseRequest
+	for _, call := range c.Calls {
+		if call.Method == "RollbackRelease" {
+			req, _ = call.Request.(*rls.RollbackReleaseRequest)
+		}
+	}
+	if req == nil || !req.DryRun {
+		t.Errorf("expected a dry run rollback to be sent to Tiller, got %v", req)
+	}
+
+	cmd = newRollbackCmd(c, &buf)
+	if err := cmd.ParseFlags([]string{"--dry-run", "--output", "json"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.RunE(cmd, []string{"funny-honey", "1"}); err == nil {
+		t.Error("expected --output json to be rejected with --dry-run")
+	}
+}
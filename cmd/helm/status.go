@@ -77,7 +77,7 @@ func newStatusCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	}
 
 	cmd.PersistentFlags().Int32Var(&status.version, "revision", 0, "if set, display the status of the named release with revision")
-	cmd.PersistentFlags().StringVarP(&status.outfmt, "output", "o", "", "output the status in the specified format (json or yaml)")
+	cmd.PersistentFlags().StringVarP(&status.outfmt, "output", "o", "table", "output the status in the specified format (json|table|yaml)")
 
 	return cmd
 }
@@ -89,7 +89,7 @@ func (s *statusCmd) run() error {
 	}
 
 	switch s.outfmt {
-	case "":
+	case "", "table":
 		PrintStatus(s.out, res)
 		return nil
 	case "json":
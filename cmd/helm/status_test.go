@@ -17,6 +17,8 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"testing"
@@ -26,6 +28,7 @@ import (
 
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/timeconv"
 )
 
@@ -57,6 +60,28 @@ func TestStatusCmd(t *testing.T) {
 				}),
 			},
 		},
+		{
+			name:     "get status of a deployed release as a table",
+			args:     []string{"flummoxed-chickadee"},
+			flags:    []string{"-o", "table"},
+			expected: outputWithStatus("DEPLOYED\n\n"),
+			rels: []*release.Release{
+				releaseMockWithStatus(&release.Status{
+					Code: release.Status_DEPLOYED,
+				}),
+			},
+		},
+		{
+			name:  "get status of a deployed release in an unknown format",
+			args:  []string{"flummoxed-chickadee"},
+			flags: []string{"-o", "xml"},
+			err:   true,
+			rels: []*release.Release{
+				releaseMockWithStatus(&release.Status{
+					Code: release.Status_DEPLOYED,
+				}),
+			},
+		},
 		{
 			name:     "get status of a deployed release with notes in json",
 			args:     []string{"flummoxed-chickadee"},
@@ -133,6 +158,45 @@ func TestStatusCmd(t *testing.T) {
 
 }
 
+func TestStatusCmdJSONOutput(t *testing.T) {
+	var buf bytes.Buffer
+	c := &helm.FakeClient{
+		Rels: []*release.Release{
+			releaseMockWithStatus(&release.Status{
+				Code:      release.Status_FAILED,
+				Notes:     "release notes",
+				Resources: "resource A\n",
+			}),
+		},
+	}
+
+	cmd := newStatusCmd(c, &buf)
+	if err := cmd.ParseFlags([]string{"-o", "json"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.RunE(cmd, []string{"flummoxed-chickadee"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var res services.GetReleaseStatusResponse
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %s", buf.String(), err)
+	}
+	if res.Name != "flummoxed-chickadee" {
+		t.Errorf("expected name %q, got %q", "flummoxed-chickadee", res.Name)
+	}
+	status := res.GetInfo().GetStatus()
+	if status.GetCode() != release.Status_FAILED {
+		t.Errorf("expected status code %s, got %s", release.Status_FAILED, status.GetCode())
+	}
+	if status.GetNotes() != "release notes" {
+		t.Errorf("expected notes %q, got %q", "release notes", status.GetNotes())
+	}
+	if status.GetResources() != "resource A\n" {
+		t.Errorf("expected resources %q, got %q", "resource A\n", status.GetResources())
+	}
+}
+
 func outputWithStatus(status string) string {
 	return fmt.Sprintf("LAST DEPLOYED: %s\nNAMESPACE: \nSTATUS: %s",
 		dateString,
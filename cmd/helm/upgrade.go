@@ -25,6 +25,7 @@ import (
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/renderutil"
 	"k8s.io/helm/pkg/storage/driver"
 )
 
@@ -74,6 +75,7 @@ type upgradeCmd struct {
 	resetValues  bool
 	reuseValues  bool
 	wait         bool
+	waitForJobs  bool
 	repoURL      string
 	username     string
 	password     string
@@ -133,6 +135,7 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&upgrade.resetValues, "reset-values", false, "when upgrading, reset the values to the ones built into the chart")
 	f.BoolVar(&upgrade.reuseValues, "reuse-values", false, "when upgrading, reuse the last release's values and merge in any overrides from the command line via --set and -f. If '--reset-values' is specified, this is ignored.")
 	f.BoolVar(&upgrade.wait, "wait", false, "if set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
+	f.BoolVar(&upgrade.waitForJobs, "wait-for-jobs", false, "if set and --wait enabled, will wait until all Jobs have been completed before marking the release as successful. It will wait for as long as --timeout")
 	f.StringVar(&upgrade.repoURL, "repo", "", "chart repository url where to locate the requested chart")
 	f.StringVar(&upgrade.username, "username", "", "chart repository username where to locate the requested chart")
 	f.StringVar(&upgrade.password, "password", "", "chart repository password where to locate the requested chart")
@@ -192,6 +195,7 @@ func (u *upgradeCmd) run() error {
 				namespace:    u.namespace,
 				timeout:      u.timeout,
 				wait:         u.wait,
+				waitForJobs:  u.waitForJobs,
 				description:  u.description,
 			}
 			return ic.run()
@@ -206,7 +210,7 @@ func (u *upgradeCmd) run() error {
 	// Check chart requirements to make sure all dependencies are present in /charts
 	if ch, err := chartutil.Load(chartPath); err == nil {
 		if req, err := chartutil.LoadRequirements(ch); err == nil {
-			if err := checkDependencies(ch, req); err != nil {
+			if err := renderutil.CheckDependencies(ch, req); err != nil {
 				return err
 			}
 		} else if err != chartutil.ErrRequirementsNotFound {
@@ -228,6 +232,7 @@ func (u *upgradeCmd) run() error {
 		helm.ResetValues(u.resetValues),
 		helm.ReuseValues(u.reuseValues),
 		helm.UpgradeWait(u.wait),
+		helm.UpgradeWaitForJobs(u.waitForJobs),
 		helm.UpgradeDescription(u.description))
 	if err != nil {
 		return fmt.Errorf("UPGRADE FAILED: %v", prettyError(err))
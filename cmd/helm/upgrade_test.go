@@ -155,6 +155,14 @@ func TestUpgradeCmd(t *testing.T) {
 			expected: "Release \"crazy-bunny\" has been upgraded. Happy Helming!\n",
 			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "crazy-bunny", Version: 2, Chart: ch2})},
 		},
+		{
+			name:     "upgrade a release with wait for jobs",
+			args:     []string{"crazy-bunny", chartPath},
+			flags:    []string{"--wait", "--wait-for-jobs"},
+			resp:     helm.ReleaseMock(&helm.MockReleaseOptions{Name: "crazy-bunny", Version: 2, Chart: ch2}),
+			expected: "Release \"crazy-bunny\" has been upgraded. Happy Helming!\n",
+			rels:     []*release.Release{helm.ReleaseMock(&helm.MockReleaseOptions{Name: "crazy-bunny", Version: 2, Chart: ch2})},
+		},
 		{
 			name:     "upgrade a release with description",
 			args:     []string{"crazy-bunny", chartPath},
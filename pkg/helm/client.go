@@ -64,6 +64,10 @@ func (h *Client) ListReleases(opts ...ReleaseListOption) (*rls.ListReleasesRespo
 		opt(&reqOpts)
 	}
 	req := &reqOpts.listReq
+	if reqOpts.listAllNamespaces {
+		// Tiller lists every namespace when none is given
+		req.Namespace = ""
+	}
 	ctx := NewContext()
 
 	if reqOpts.before != nil {
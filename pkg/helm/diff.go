@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	util "k8s.io/helm/pkg/releaseutil"
+)
+
+// DiffReleases returns a unified diff of the manifests of two releases, such
+// as two revisions of the same release. Each resource is diffed separately,
+// matched by kind, namespace and name, so that reordering resources does not show up as
+// a change. Resources only in a appear as removed, and those only in b as added.
+// The result is empty if the manifests describe the same resources.
+func DiffReleases(a, b *release.Release) (string, error) {
+	from, err := manifestResources(a.GetManifest())
+	if err != nil {
+		return "", fmt.Errorf("parsing manifest of %s (revision %d): %s", a.GetName(), a.GetVersion(), err)
+	}
+	to, err := manifestResources(b.GetManifest())
+	if err != nil {
+		return "", fmt.Errorf("parsing manifest of %s (revision %d): %s", b.GetName(), b.GetVersion(), err)
+	}
+
+	keys := []string{}
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var out bytes.Buffer
+	for _, k := range keys {
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(from[k]),
+			B:        difflib.SplitLines(to[k]),
+			FromFile: fmt.Sprintf("%s (revision %d)", k, a.GetVersion()),
+			ToFile:   fmt.Sprintf("%s (revision %d)", k, b.GetVersion()),
+			Context:  3,
+		})
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(diff)
+	}
+	return out.String(), nil
+}
+
+// manifestResources splits a manifest into its resources, keyed by
+// kind/namespace/name, leaving out the namespace if it has none. Documents
+// without a kind, such as those only holding comments, are skipped.
+func manifestResources(manifest string) (map[string]string, error) {
+	resources := map[string]string{}
+	for _, doc := range util.SplitManifests(manifest) {
+		var head util.SimpleHead
+		if err := yaml.Unmarshal([]byte(doc), &head); err != nil {
+			return nil, err
+		}
+		if head.Kind == "" {
+			continue
+		}
+		key := head.Kind + "/"
+		if head.Metadata != nil {
+			if head.Metadata.Namespace != "" {
+				key += head.Metadata.Namespace + "/"
+			}
+			key += head.Metadata.Name
+		}
+		if _, ok := resources[key]; ok {
+			return nil, fmt.Errorf("%s is defined more than once", key)
+		}
+		resources[key] = doc + "\n"
+	}
+	return resources, nil
+}
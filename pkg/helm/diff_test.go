@@ -0,0 +1,125 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestDiffReleases(t *testing.T) {
+	const (
+		configMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: value
+`
+		secret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+`
+		service = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+	)
+	manifest := func(docs ...string) *release.Release {
+		return &release.Release{Name: "diff", Version: 1, Manifest: "---\n" + strings.Join(docs, "---\n")}
+	}
+
+	tests := []struct {
+		name   string
+		a, b   *release.Release
+		expect []string
+	}{
+		{
+			name: "added resource",
+			a:    manifest(configMap),
+			b:    manifest(configMap, service),
+			expect: []string{
+				"--- Service/web (revision 1)",
+				"+kind: Service",
+			},
+		},
+		{
+			name: "removed resource",
+			a:    manifest(secret, configMap),
+			b:    manifest(configMap),
+			expect: []string{
+				"--- Secret/secret (revision 1)",
+				"-kind: Secret",
+			},
+		},
+		{
+			name: "modified resource",
+			a:    manifest(configMap, service),
+			b:    manifest(service, strings.Replace(configMap, "key: value", "key: changed", 1)),
+			expect: []string{
+				"--- ConfigMap/config (revision 1)",
+				"-  key: value",
+				"+  key: changed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		diff, err := DiffReleases(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		for _, e := range tt.expect {
+			if !strings.Contains(diff, e) {
+				t.Errorf("%s: expected diff to contain %q, got\n%s", tt.name, e, diff)
+			}
+		}
+		if strings.Count(diff, "--- ") != 1 {
+			t.Errorf("%s: expected a single resource to differ, got\n%s", tt.name, diff)
+		}
+	}
+
+	diff, err := DiffReleases(manifest(configMap, service), manifest(service, configMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Errorf("expected reordered resources not to differ, got\n%s", diff)
+	}
+
+	inNamespace := func(doc, namespace string) string {
+		return strings.Replace(doc, "metadata:\n", "metadata:\n  namespace: "+namespace+"\n", 1)
+	}
+	diff, err = DiffReleases(
+		manifest(inNamespace(configMap, "one"), inNamespace(configMap, "two")),
+		manifest(inNamespace(configMap, "one"), inNamespace(strings.Replace(configMap, "key: value", "key: changed", 1), "two")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "--- ConfigMap/two/config (revision 1)") || strings.Count(diff, "--- ") != 1 {
+		t.Errorf("expected only the resource in namespace two to differ, got\n%s", diff)
+	}
+
+	if _, err := DiffReleases(manifest(configMap, configMap), manifest(configMap)); err == nil {
+		t.Error("expected an error for a resource defined twice")
+	}
+}
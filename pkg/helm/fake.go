@@ -17,16 +17,29 @@ limitations under the License.
 package helm // import "k8s.io/helm/pkg/helm"
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/timestamp"
+	"golang.org/x/net/context"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/manifest"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	rls "k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/proto/hapi/version"
+	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/renderutil"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/timeconv"
 )
 
 // FakeClient implements Interface
@@ -34,6 +47,114 @@ type FakeClient struct {
 	Rels      []*release.Release
 	Responses map[string]release.TestRun_Status
 	Opts      options
+	// RenderManifests renders the hooks and manifest of installed and upgraded
+	// releases from the given chart, instead of using the mock fixtures.
+	RenderManifests bool
+	// RenderOptions are passed to RenderReleaseMock when RenderManifests is set.
+	RenderOptions []RenderOption
+	// Delay is how long each call takes before returning, to simulate a slow Tiller.
+	Delay time.Duration
+	// Context, if set, cuts Delay short once it is done, in which case calls
+	// return its error instead of being made.
+	Context context.Context
+	// Labels holds the labels of releases, by release name. Releases do not
+	// carry labels themselves, so this is what DeleteReleasesBySelector matches.
+	Labels map[string]map[string]string
+	// MaxHistory, when greater than zero, caps the number of revisions kept per
+	// release. The oldest revisions that are not DEPLOYED are trimmed first.
+	MaxHistory int
+	// ForceReplaces simulates a FAILED release whose resources can not be updated
+	// in place: upgrading it fails unless UpgradeForce is used to replace them,
+	// after which it is DEPLOYED again.
+	ForceReplaces bool
+	// RequireDeployed rejects upgrades of a release whose latest revision is not
+	// DEPLOYED, such as a FAILED or PENDING_UPGRADE one, unless UpgradeForce is used.
+	RequireDeployed bool
+	// GenerateNames gives installs without a release name a generated one that
+	// is not in use, like Tiller does. Otherwise the empty name is used as is.
+	GenerateNames bool
+	// TestDurations holds how long the release test reporting each of Responses
+	// takes to run, by message. A test that takes longer than the timeout given
+	// with ReleaseTestTimeout fails with a timeout error instead.
+	TestDurations map[string]time.Duration
+	// Resources holds the resource table ReleaseStatus reports for a release, by
+	// release name, as Tiller would render it from the live cluster.
+	Resources map[string]string
+	// Now returns the time installs, upgrades and rollbacks stamp releases with.
+	// By default a test clock starts at the date of ReleaseMock, and advances by
+	// a second every time it is read.
+	Now func() time.Time
+	// Calls records every call made to the client, in order.
+	Calls []FakeCall
+
+	// mu is only held by upgrades, so that concurrent ones get distinct
+	// revisions, and by the cleanup of failed test hooks that RunReleaseTest
+	// does in the background. Other methods do not take it, so apart from
+	// concurrent upgrades a FakeClient is not safe for concurrent use.
+	mu sync.Mutex
+	// failInstallsAfter is the number of resources installs create before
+	// failing, when failInstalls is set with FailInstallsAfter.
+	failInstallsAfter int
+	failInstalls      bool
+	// clock is the current time of the default test clock.
+	clock time.Time
+}
+
+// delay waits for Delay to pass, or for Context to be done, in which case it
+// returns the error of Context.
+func (c *FakeClient) delay() error {
+	ctx := c.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if c.Delay <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(c.Delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// now returns the time to stamp a release with, from Now or the test clock.
+func (c *FakeClient) now() *timestamp.Timestamp {
+	if c.Now != nil {
+		return timeconv.Timestamp(c.Now())
+	}
+	if c.clock.IsZero() {
+		c.clock = time.Unix(mockDeployedSeconds, 0)
+	}
+	c.clock = c.clock.Add(time.Second)
+	return timeconv.Timestamp(c.clock)
+}
+
+// FakeCall records a single call made to a FakeClient.
+type FakeCall struct {
+	// Method is the name of the method that was called.
+	Method string
+	// Release is the name of the release the call was made for, if any.
+	Release string
+	// Namespace is the namespace the call was made for, if any.
+	Namespace string
+	// Request is a copy of the request Tiller would have received, with all options applied.
+	Request proto.Message
+}
+
+func (c *FakeClient) record(method, rlsName, namespace string, req proto.Message) {
+	if req != nil {
+		req = proto.Clone(req)
+	}
+	c.Calls = append(c.Calls, FakeCall{Method: method, Release: rlsName, Namespace: namespace, Request: req})
+}
+
+// ResetCalls forgets all recorded calls.
+func (c *FakeClient) ResetCalls() {
+	c.Calls = nil
 }
 
 // Option returns the fake release client
@@ -47,65 +168,227 @@ func (c *FakeClient) Option(opts ...Option) Interface {
 var _ Interface = &FakeClient{}
 var _ Interface = (*FakeClient)(nil)
 
-// ListReleases lists the current releases
+// ListReleases lists the current releases, from every namespace unless
+// ReleaseListNamespace is used. ReleaseListChart is honored as well. Other
+// status codes are not filtered on, but DELETED releases are only listed with
+// ReleaseListIncludeDeleted or when requested with ReleaseListStatuses.
 func (c *FakeClient) ListReleases(opts ...ReleaseListOption) (*rls.ListReleasesResponse, error) {
+	if err := c.delay(); err != nil {
+		return nil, err
+	}
 	reqOpts := c.Opts
 	for _, opt := range opts {
 		opt(&reqOpts)
 	}
 	req := &reqOpts.listReq
-	rels := c.Rels
-	count := int64(len(c.Rels))
+	if reqOpts.listAllNamespaces {
+		req.Namespace = ""
+	}
+	c.record("ListReleases", "", req.Namespace, req)
+	rels := []*release.Release{}
+	for _, rel := range c.Rels {
+		// Like Tiller, an empty namespace lists every namespace
+		if req.Namespace != "" && rel.Namespace != req.Namespace {
+			continue
+		}
+		if req.Chart != "" && rel.GetChart().GetMetadata().GetName() != req.Chart {
+			continue
+		}
+		if rel.GetInfo().GetStatus().GetCode() == release.Status_DELETED && !listsDeleted(req) {
+			continue
+		}
+		rels = append(rels, rel)
+	}
+	rels = latestRevisions(rels)
+	total := int64(len(rels))
+	// Like Tiller, a page starts at the release named by the offset, and Next
+	// names the first release of the following page, if any.
+	if req.Offset != "" {
+		i := -1
+		for j, rel := range rels {
+			if rel.GetName() == req.Offset {
+				i = j
+				break
+			}
+		}
+		if i == -1 {
+			return nil, fmt.Errorf("offset %q not found", req.Offset)
+		}
+		rels = rels[i:]
+	}
+	count := int64(len(rels))
 	var next string
 	limit := req.GetLimit()
 	// TODO: Handle all other options.
 	if limit != 0 && limit < count {
+		next = rels[limit].GetName()
 		rels = rels[:limit]
 		count = limit
-		next = c.Rels[limit].GetName()
 	}
 
 	resp := &rls.ListReleasesResponse{
 		Count:    count,
+		Total:    total,
 		Releases: rels,
-	}
-	if next != "" {
-		resp.Next = next
+		Next:     next,
 	}
 	return resp, nil
 }
 
+// latestRevisions keeps only the highest revision of each release, like Tiller,
+// so that every name appears once and can be used as a page offset.
+func latestRevisions(rels []*release.Release) []*release.Release {
+	index := map[string]int{}
+	latest := []*release.Release{}
+	for _, rel := range rels {
+		i, ok := index[rel.GetName()]
+		if !ok {
+			index[rel.GetName()] = len(latest)
+			latest = append(latest, rel)
+			continue
+		}
+		if rel.GetVersion() > latest[i].GetVersion() {
+			latest[i] = rel
+		}
+	}
+	return latest
+}
+
+// listsDeleted reports whether DELETED releases are requested by req.
+func listsDeleted(req *rls.ListReleasesRequest) bool {
+	if req.IncludeDeleted {
+		return true
+	}
+	for _, code := range req.StatusCodes {
+		if code == release.Status_DELETED {
+			return true
+		}
+	}
+	return false
+}
+
 // InstallRelease creates a new release and returns a InstallReleaseResponse containing that release
 func (c *FakeClient) InstallRelease(chStr, ns string, opts ...InstallOption) (*rls.InstallReleaseResponse, error) {
-	chart := &chart.Chart{}
-	return c.InstallReleaseFromChart(chart, ns, opts...)
+	return c.installRelease("InstallRelease", &chart.Chart{}, ns, opts...)
 }
 
-// InstallReleaseFromChart adds a new MockRelease to the fake client and returns a InstallReleaseResponse containing that release
+// InstallReleaseFromChart adds a new MockRelease to the fake client and returns a InstallReleaseResponse containing that release.
+// With InstallDryRun the release is returned, rendered if RenderManifests is set, but not added.
 func (c *FakeClient) InstallReleaseFromChart(chart *chart.Chart, ns string, opts ...InstallOption) (*rls.InstallReleaseResponse, error) {
+	return c.installRelease("InstallReleaseFromChart", chart, ns, opts...)
+}
+
+func (c *FakeClient) installRelease(method string, chart *chart.Chart, ns string, opts ...InstallOption) (*rls.InstallReleaseResponse, error) {
+	if err := c.delay(); err != nil {
+		return nil, err
+	}
+	reqOpts := c.Opts
 	for _, opt := range opts {
-		opt(&c.Opts)
+		opt(&reqOpts)
 	}
+	req := &reqOpts.instReq
+	req.Namespace = ns
+	req.DryRun = reqOpts.dryRun
+	req.DisableHooks = reqOpts.disableHooks
+	req.DisableCrdHook = reqOpts.disableCRDHook
+	req.ReuseName = reqOpts.reuseName
+	c.record(method, req.Name, ns, req)
 
-	releaseName := c.Opts.instReq.Name
-	releaseDescription := c.Opts.instReq.Description
+	releaseName := req.Name
+	releaseDescription := req.Description
+	if releaseName == "" && c.GenerateNames {
+		name, err := c.generateName()
+		if err != nil {
+			return nil, err
+		}
+		releaseName = name
+	}
 
 	// Check to see if the release already exists.
-	rel, err := c.ReleaseStatus(releaseName, nil)
-	if err == nil && rel != nil {
+	if c.findRelease(releaseName, 0) != nil {
 		return nil, errors.New("cannot re-use a name that is still in use")
 	}
 
-	release := ReleaseMock(&MockReleaseOptions{Name: releaseName, Namespace: ns, Description: releaseDescription})
-	c.Rels = append(c.Rels, release)
+	mockOpts := &MockReleaseOptions{Name: releaseName, Namespace: ns, Description: releaseDescription}
+	if c.RenderManifests {
+		mockOpts.Chart = chart
+		mockOpts.Config = req.Values
+	}
+	release := ReleaseMock(mockOpts)
+	release.Info.FirstDeployed = c.now()
+	release.Info.LastDeployed = release.Info.FirstDeployed
+	if c.RenderManifests {
+		if err := RenderReleaseMock(release, false, c.RenderOptions...); err != nil {
+			return nil, err
+		}
+	}
+	// Like Tiller, a dry run returns the release without storing it
+	var err error
+	if !req.DryRun {
+		err = c.failInstall(release)
+		c.Rels = append(c.Rels, release)
+		c.trimHistory(releaseName)
+	}
 
 	return &rls.InstallReleaseResponse{
 		Release: release,
-	}, nil
+	}, err
+}
+
+// FailInstallsAfter makes installs with more than n resources fail after
+// creating the first n of them, like Tiller when creating a resource fails.
+// The release is stored FAILED, with a manifest of the resources created, and
+// returned along with the error. A negative n makes installs succeed again.
+func (c *FakeClient) FailInstallsAfter(n int) {
+	c.failInstalls = n >= 0
+	c.failInstallsAfter = n
+}
+
+// failInstall truncates the manifest of rel to the resources created before the
+// simulated failure set with FailInstallsAfter, if any, and marks it FAILED.
+func (c *FakeClient) failInstall(rel *release.Release) error {
+	if !c.failInstalls {
+		return nil
+	}
+	docs := relutil.SplitManifests(rel.Manifest)
+	if len(docs) <= c.failInstallsAfter {
+		return nil
+	}
+
+	b := bytes.NewBuffer(nil)
+	for i := 0; i < c.failInstallsAfter; i++ {
+		b.WriteString("\n---\n" + docs[fmt.Sprintf("manifest-%d", i)])
+	}
+	err := fmt.Errorf("release %s failed: simulated failure after creating %d of %d resources", rel.Name, c.failInstallsAfter, len(docs))
+	rel.Manifest = b.String()
+	rel.Info.Status.Code = release.Status_FAILED
+	rel.Info.Description = err.Error()
+	return err
+}
+
+// generateName returns a generated release name that is not in use, trying as
+// many times as Tiller does.
+func (c *FakeClient) generateName() (string, error) {
+	for i := 0; i < 5; i++ {
+		name := mockReleaseName()
+		if c.findRelease(name, 0) == nil {
+			return name, nil
+		}
+	}
+	return "", errors.New("no available release name found")
 }
 
 // DeleteRelease deletes a release from the FakeClient
 func (c *FakeClient) DeleteRelease(rlsName string, opts ...DeleteOption) (*rls.UninstallReleaseResponse, error) {
+	if err := c.delay(); err != nil {
+		return nil, err
+	}
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	reqOpts.uninstallReq.Name = rlsName
+	c.record("DeleteRelease", rlsName, "", &reqOpts.uninstallReq)
 	for i, rel := range c.Rels {
 		if rel.Name == rlsName {
 			c.Rels = append(c.Rels[:i], c.Rels[i+1:]...)
@@ -118,8 +401,58 @@ func (c *FakeClient) DeleteRelease(rlsName string, opts ...DeleteOption) (*rls.U
 	return nil, fmt.Errorf("No such release: %s", rlsName)
 }
 
+// DeleteReleasesBySelector deletes every release whose Labels match all of
+// the key/value pairs in selector, and returns the deleted releases.
+func (c *FakeClient) DeleteReleasesBySelector(selector map[string]string, opts ...DeleteOption) ([]*release.Release, error) {
+	c.record("DeleteReleasesBySelector", "", "", nil)
+	if len(selector) == 0 {
+		return nil, errors.New("a selector is required to delete releases")
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, rel := range c.Rels {
+		if !seen[rel.Name] && matchesLabels(c.Labels[rel.Name], selector) {
+			seen[rel.Name] = true
+			names = append(names, rel.Name)
+		}
+	}
+
+	// Each release is deleted once, with all of its revisions, and reported by
+	// its latest revision.
+	deleted := []*release.Release{}
+	for _, name := range names {
+		latest := c.findRelease(name, 0)
+		if _, err := c.DeleteRelease(name, opts...); err != nil {
+			return deleted, err
+		}
+		kept := c.Rels[:0]
+		for _, rel := range c.Rels {
+			if rel.Name != name {
+				kept = append(kept, rel)
+			}
+		}
+		c.Rels = kept
+		deleted = append(deleted, latest)
+	}
+	return deleted, nil
+}
+
+func matchesLabels(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
 // GetVersion returns a fake version
 func (c *FakeClient) GetVersion(opts ...VersionOption) (*rls.GetVersionResponse, error) {
+	if err := c.delay(); err != nil {
+		return nil, err
+	}
+	c.record("GetVersion", "", "", &rls.GetVersionRequest{})
 	return &rls.GetVersionResponse{
 		Version: &version.Version{
 			SemVer: "1.2.3-fakeclient+testonly",
@@ -129,65 +462,421 @@ func (c *FakeClient) GetVersion(opts ...VersionOption) (*rls.GetVersionResponse,
 
 // UpdateRelease returns an UpdateReleaseResponse containing the updated release, if it exists
 func (c *FakeClient) UpdateRelease(rlsName string, chStr string, opts ...UpdateOption) (*rls.UpdateReleaseResponse, error) {
-	return c.UpdateReleaseFromChart(rlsName, &chart.Chart{}, opts...)
+	return c.updateRelease("UpdateRelease", rlsName, &chart.Chart{}, opts...)
 }
 
-// UpdateReleaseFromChart returns an UpdateReleaseResponse containing the updated release, if it exists
+// UpdateReleaseFromChart returns an UpdateReleaseResponse containing the updated release, if it exists.
+// If RenderManifests is set, the release is replaced by a new revision rendered from chart.
+// Otherwise only the hooks of the release are rendered from chart, if it has metadata.
 func (c *FakeClient) UpdateReleaseFromChart(rlsName string, chart *chart.Chart, opts ...UpdateOption) (*rls.UpdateReleaseResponse, error) {
-	// Check to see if the release already exists.
-	rel, err := c.ReleaseContent(rlsName, nil)
-	if err != nil {
+	return c.updateRelease("UpdateReleaseFromChart", rlsName, chart, opts...)
+}
+
+func (c *FakeClient) updateRelease(method, rlsName string, chart *chart.Chart, opts ...UpdateOption) (*rls.UpdateReleaseResponse, error) {
+	if err := c.delay(); err != nil {
 		return nil, err
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.updateReq
+	req.Name = rlsName
+	req.DryRun = reqOpts.dryRun
+	req.DisableHooks = reqOpts.disableHooks
+	req.Recreate = reqOpts.recreate
+	req.Force = reqOpts.force
+	req.ResetValues = reqOpts.resetValues
+	req.ReuseValues = reqOpts.reuseValues
+	c.record(method, rlsName, "", req)
+
+	// Check to see if the release already exists. This is its highest revision,
+	// so the new one follows it even if the history has gaps.
+	rel := c.findRelease(rlsName, 0)
+	if rel == nil {
+		return nil, fmt.Errorf("No such release: %s", rlsName)
+	}
+	code := rel.GetInfo().GetStatus().GetCode()
+	failed := code == release.Status_FAILED
+	if c.ForceReplaces && failed && !req.Force {
+		return nil, fmt.Errorf("UPGRADE FAILED: %s can not be updated in place, use force to replace its resources", rlsName)
+	}
+	if c.RequireDeployed && code != release.Status_DEPLOYED && !req.Force {
+		return nil, fmt.Errorf("UPGRADE FAILED: the latest revision of %s is %s, use force to upgrade it", rlsName, code)
+	}
+	if !c.RenderManifests {
+		upgraded := rel
+		if req.DryRun {
+			// Like Tiller, a dry run returns the release without storing it
+			upgraded = proto.Clone(rel).(*release.Release)
+		}
+		if chart.GetMetadata() != nil {
+			// The hooks come from the new chart, so those it removed do not persist
+			rendered := *upgraded
+			rendered.Chart = chart
+			if req.Values != nil {
+				rendered.Config = req.Values
+			}
+			hooks, _, err := RenderReleaseManifests(&rendered, true, c.RenderOptions...)
+			if err != nil {
+				return nil, err
+			}
+			upgraded.Chart = chart
+			upgraded.Hooks = hooks
+		}
+		if c.ForceReplaces && failed {
+			// The resources were replaced, so the release is deployed again
+			upgraded.Info.Status.Code = release.Status_DEPLOYED
+		}
+		if upgraded.Info != nil {
+			upgraded.Info.LastDeployed = c.now()
+		}
+		return &rls.UpdateReleaseResponse{Release: upgraded}, nil
+	}
 
-	return &rls.UpdateReleaseResponse{Release: rel.Release}, nil
+	newRelease := ReleaseMock(&MockReleaseOptions{
+		Name:        rel.Name,
+		Version:     rel.Version + 1,
+		Chart:       chart,
+		Config:      req.Values,
+		Namespace:   rel.Namespace,
+		Description: req.Description,
+	})
+	if first := rel.GetInfo().GetFirstDeployed(); first != nil {
+		newRelease.Info.FirstDeployed = first
+	}
+	newRelease.Info.LastDeployed = c.now()
+	if err := RenderReleaseMock(newRelease, true, c.RenderOptions...); err != nil {
+		return nil, err
+	}
+	if req.DryRun {
+		return &rls.UpdateReleaseResponse{Release: newRelease}, nil
+	}
+	if rel.GetInfo().GetStatus() != nil {
+		rel.Info.Status.Code = release.Status_SUPERSEDED
+	}
+	c.Rels = append(c.Rels, newRelease)
+	c.trimHistory(rlsName)
+
+	return &rls.UpdateReleaseResponse{Release: newRelease}, nil
 }
 
-// RollbackRelease returns nil, nil
+// RollbackRelease returns a RollbackReleaseResponse containing the matching release, or nil, nil if there is none.
+// It returns an error if the target revision is the current one, or if it has been deleted or failed
+// without leaving a manifest to restore.
 func (c *FakeClient) RollbackRelease(rlsName string, opts ...RollbackOption) (*rls.RollbackReleaseResponse, error) {
-	return nil, nil
+	if err := c.delay(); err != nil {
+		return nil, err
+	}
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	req := &reqOpts.rollbackReq
+	req.Recreate = reqOpts.recreate
+	req.Force = reqOpts.force
+	req.DisableHooks = reqOpts.disableHooks
+	req.DryRun = reqOpts.dryRun
+	req.Name = rlsName
+	c.record("RollbackRelease", rlsName, "", req)
+
+	var current *release.Release
+	for _, rel := range c.Rels {
+		if rel.Name == rlsName && (current == nil || rel.Version > current.Version) {
+			current = rel
+		}
+	}
+	if current == nil {
+		return nil, nil
+	}
+
+	target := req.Version
+	if target == 0 {
+		target = current.Version - 1
+	}
+	if target == current.Version {
+		return nil, fmt.Errorf("release: %q is already at revision %d", rlsName, target)
+	}
+	prev := c.findRelease(rlsName, target)
+	if prev == nil {
+		return nil, fmt.Errorf("release: %q revision %d not found", rlsName, target)
+	}
+	switch code := prev.GetInfo().GetStatus().GetCode(); code {
+	case release.Status_DELETED, release.Status_DELETING:
+		return nil, fmt.Errorf("release: %q revision %d is %s and cannot be rolled back to", rlsName, target, code)
+	case release.Status_FAILED:
+		if prev.Manifest == "" {
+			return nil, fmt.Errorf("release: %q revision %d is FAILED and has no manifest to roll back to", rlsName, target)
+		}
+	}
+	if current.Info != nil && !req.DryRun {
+		current.Info.LastDeployed = c.now()
+	}
+	return &rls.RollbackReleaseResponse{Release: current}, nil
 }
 
 // ReleaseStatus returns a release status response with info from the matching release name.
+// If a version is requested with StatusReleaseVersion, only that revision of the release matches.
 func (c *FakeClient) ReleaseStatus(rlsName string, opts ...StatusOption) (*rls.GetReleaseStatusResponse, error) {
+	if err := c.delay(); err != nil {
+		return nil, err
+	}
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	reqOpts.statusReq.Name = rlsName
+	c.record("ReleaseStatus", rlsName, "", &reqOpts.statusReq)
+	rel := c.findRelease(rlsName, reqOpts.statusReq.Version)
+	if rel == nil {
+		return nil, fmt.Errorf("No such release: %s", rlsName)
+	}
+	info := rel.Info
+	if resources, ok := c.Resources[rlsName]; ok {
+		// Report the resources without storing them in the release
+		info = &release.Info{Status: &release.Status{}}
+		if rel.Info != nil {
+			info = proto.Clone(rel.Info).(*release.Info)
+		}
+		if info.Status == nil {
+			info.Status = &release.Status{}
+		}
+		info.Status.Resources = resources
+	}
+	return &rls.GetReleaseStatusResponse{
+		Name:      rel.Name,
+		Info:      info,
+		Namespace: rel.Namespace,
+	}, nil
+}
+
+// SetReleaseStatus forces the stored revision of a release into the given
+// status, for instance to simulate an operation that never finished. A
+// version of 0 selects the latest revision.
+func (c *FakeClient) SetReleaseStatus(rlsName string, version int32, code release.Status_Code) error {
+	rel := c.findRelease(rlsName, version)
+	if rel == nil {
+		return fmt.Errorf("No such release: %s", rlsName)
+	}
+	if rel.Info == nil {
+		rel.Info = &release.Info{}
+	}
+	if rel.Info.Status == nil {
+		rel.Info.Status = &release.Status{}
+	}
+	rel.Info.Status.Code = code
+	return nil
+}
+
+// GetRevision returns the stored revision of a release, or the latest one if
+// version is 0. Unlike ReleaseContent, the stored release itself is returned.
+func (c *FakeClient) GetRevision(rlsName string, version int32) (*release.Release, error) {
+	rel := c.findRelease(rlsName, version)
+	if rel == nil {
+		return nil, driver.ErrReleaseNotFound(fmt.Sprintf("%s.v%d", rlsName, version))
+	}
+	return rel, nil
+}
+
+// Deployed returns the DEPLOYED revision of a release, which is its current
+// one. It is an error for a release to have no DEPLOYED revision, or several.
+func (c *FakeClient) Deployed(rlsName string) (*release.Release, error) {
+	var deployed []*release.Release
 	for _, rel := range c.Rels {
-		if rel.Name == rlsName {
-			return &rls.GetReleaseStatusResponse{
-				Name:      rel.Name,
-				Info:      rel.Info,
-				Namespace: rel.Namespace,
-			}, nil
+		if rel.Name == rlsName && rel.GetInfo().GetStatus().GetCode() == release.Status_DEPLOYED {
+			deployed = append(deployed, rel)
 		}
 	}
-	return nil, fmt.Errorf("No such release: %s", rlsName)
+	switch len(deployed) {
+	case 0:
+		return nil, fmt.Errorf("%q has no deployed releases", rlsName)
+	case 1:
+		return deployed[0], nil
+	default:
+		return nil, fmt.Errorf("%q has %d deployed releases", rlsName, len(deployed))
+	}
 }
 
 // ReleaseContent returns the configuration for the matching release name in the fake release client.
+// If a version is requested with ContentReleaseVersion, only that revision of the release matches.
+// If RenderManifests is set and the release has no manifest, the returned copy is rendered from its chart.
 func (c *FakeClient) ReleaseContent(rlsName string, opts ...ContentOption) (resp *rls.GetReleaseContentResponse, err error) {
+	if err := c.delay(); err != nil {
+		return nil, err
+	}
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	reqOpts.contentReq.Name = rlsName
+	c.record("ReleaseContent", rlsName, "", &reqOpts.contentReq)
+	rel := c.findRelease(rlsName, reqOpts.contentReq.Version)
+	if rel == nil {
+		return resp, fmt.Errorf("No such release: %s", rlsName)
+	}
+	if c.RenderManifests && rel.Manifest == "" {
+		rel = proto.Clone(rel).(*release.Release)
+		if err := RenderReleaseMock(rel, false, c.RenderOptions...); err != nil {
+			return nil, err
+		}
+	}
+	return &rls.GetReleaseContentResponse{
+		Release: rel,
+	}, nil
+}
+
+// findRelease returns the latest stored revision of the release with the given
+// name, or nil if there is none. A non-zero version only matches that revision.
+func (c *FakeClient) findRelease(rlsName string, version int32) *release.Release {
+	var found *release.Release
 	for _, rel := range c.Rels {
-		if rel.Name == rlsName {
-			return &rls.GetReleaseContentResponse{
-				Release: rel,
-			}, nil
+		if rel.Name != rlsName {
+			continue
+		}
+		if version != 0 && rel.Version == version {
+			return rel
+		}
+		if version == 0 && (found == nil || rel.Version > found.Version) {
+			found = rel
+		}
+	}
+	return found
+}
+
+// trimHistory removes the oldest revisions of a release that are not DEPLOYED
+// until at most MaxHistory revisions remain.
+func (c *FakeClient) trimHistory(rlsName string) {
+	if c.MaxHistory <= 0 {
+		return
+	}
+	for {
+		var count int
+		var oldest *release.Release
+		for _, rel := range c.Rels {
+			if rel.Name != rlsName {
+				continue
+			}
+			count++
+			if rel.GetInfo().GetStatus().GetCode() != release.Status_DEPLOYED && (oldest == nil || rel.Version < oldest.Version) {
+				oldest = rel
+			}
+		}
+		if count <= c.MaxHistory || oldest == nil {
+			return
+		}
+		for i, rel := range c.Rels {
+			if rel == oldest {
+				c.Rels = append(c.Rels[:i], c.Rels[i+1:]...)
+				break
+			}
 		}
 	}
-	return resp, fmt.Errorf("No such release: %s", rlsName)
 }
 
-// ReleaseHistory returns a release's revision history.
+// ReleaseHistory returns a release's revision history, newest first. Like Tiller,
+// at most the number of revisions requested with WithMaxHistory are returned,
+// and a release without any revision is not found.
 func (c *FakeClient) ReleaseHistory(rlsName string, opts ...HistoryOption) (*rls.GetHistoryResponse, error) {
-	return &rls.GetHistoryResponse{Releases: c.Rels}, nil
+	if err := c.delay(); err != nil {
+		return nil, err
+	}
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	reqOpts.histReq.Name = rlsName
+	c.record("ReleaseHistory", rlsName, "", &reqOpts.histReq)
+
+	h := []*release.Release{}
+	for _, rel := range c.Rels {
+		if rel.Name == rlsName {
+			h = append(h, rel)
+		}
+	}
+	if len(h) == 0 {
+		return nil, driver.ErrReleaseNotFound(rlsName)
+	}
+	relutil.Reverse(h, relutil.SortByRevision)
+	if max := int(reqOpts.histReq.Max); max > 0 && len(h) > max {
+		h = h[:max]
+	}
+	return &rls.GetHistoryResponse{Releases: h}, nil
+}
+
+// AllRevisions returns every stored revision of every release, like
+// enumerating the raw storage, ordered by release name and then by revision.
+// Rels itself is left in its order.
+func (c *FakeClient) AllRevisions() []*release.Release {
+	rels := make([]*release.Release, len(c.Rels))
+	copy(rels, c.Rels)
+	sort.SliceStable(rels, func(i, j int) bool {
+		if rels[i].Name != rels[j].Name {
+			return rels[i].Name < rels[j].Name
+		}
+		return rels[i].Version < rels[j].Version
+	})
+	return rels
+}
+
+// SeedReleases stores pre-built releases, such as those made with ReleaseMock,
+// as they are. This is meant to set up fixtures, including histories with
+// several revisions. It returns an error, without storing anything, if a
+// revision of a release is stored already or given twice.
+func (c *FakeClient) SeedReleases(rels ...*release.Release) error {
+	seen := map[string]bool{}
+	for _, rel := range c.Rels {
+		seen[fmt.Sprintf("%s.v%d", rel.Name, rel.Version)] = true
+	}
+	for _, rel := range rels {
+		key := fmt.Sprintf("%s.v%d", rel.Name, rel.Version)
+		if seen[key] {
+			return fmt.Errorf("release %s revision %d already exists", rel.Name, rel.Version)
+		}
+		seen[key] = true
+	}
+	c.ForceSeedReleases(rels...)
+	return nil
+}
+
+// ForceSeedReleases stores pre-built releases like SeedReleases, without
+// checking whether they are stored already.
+func (c *FakeClient) ForceSeedReleases(rels ...*release.Release) {
+	c.Rels = append(c.Rels, rels...)
 }
 
-// RunReleaseTest executes a pre-defined tests on a release
+// RunReleaseTest executes a pre-defined tests on a release. Tests that take
+// longer than the requested timeout, according to TestDurations, fail.
 func (c *FakeClient) RunReleaseTest(rlsName string, opts ...ReleaseTestOption) (<-chan *rls.TestReleaseResponse, <-chan error) {
+	reqOpts := c.Opts
+	for _, opt := range opts {
+		opt(&reqOpts)
+	}
+	reqOpts.testReq.Name = rlsName
+	c.record("RunReleaseTest", rlsName, "", &reqOpts.testReq)
 
 	results := make(chan *rls.TestReleaseResponse)
 	errc := make(chan error, 1)
 
 	go func() {
+		if err := c.delay(); err != nil {
+			errc <- err
+			close(results)
+			close(errc)
+			return
+		}
+
 		var wg sync.WaitGroup
+		failed := false
 		for m, s := range c.Responses {
+			if timeout := reqOpts.testReq.Timeout; timeout > 0 && c.TestDurations[m] > time.Duration(timeout)*time.Second {
+				m = fmt.Sprintf("ERROR: %s timed out after %ds", m, timeout)
+				s = release.TestRun_FAILURE
+			}
+			if s == release.TestRun_FAILURE {
+				failed = true
+			}
 			wg.Add(1)
 
 			go func(msg string, status release.TestRun_Status) {
@@ -197,6 +886,9 @@ func (c *FakeClient) RunReleaseTest(rlsName string, opts ...ReleaseTestOption) (
 		}
 
 		wg.Wait()
+		if failed {
+			c.deleteFailedTestHooks(rlsName)
+		}
 		close(results)
 		close(errc)
 	}()
@@ -204,11 +896,80 @@ func (c *FakeClient) RunReleaseTest(rlsName string, opts ...ReleaseTestOption) (
 	return results, errc
 }
 
+// deleteFailedTestHooks removes the test hooks with the hook-failed delete
+// policy from the latest revision of a release, as Tiller deletes them once a
+// test run fails.
+func (c *FakeClient) deleteFailedTestHooks(rlsName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rel := c.findRelease(rlsName, 0)
+	if rel == nil {
+		return
+	}
+	deleted := map[*release.Hook]bool{}
+	for _, h := range hooks.FilterTestHooks(rel.Hooks) {
+		if hooks.HasDeletePolicy(h, release.Hook_FAILED) {
+			deleted[h] = true
+		}
+	}
+	var kept []*release.Hook
+	for _, h := range rel.Hooks {
+		if !deleted[h] {
+			kept = append(kept, h)
+		}
+	}
+	rel.Hooks = kept
+}
+
+// TestSummary tallies the responses of a release test run.
+type TestSummary struct {
+	Passed  int
+	Failed  int
+	Running int
+	Unknown int
+	// Messages are the messages of all responses, in the order they were received.
+	Messages []string
+}
+
+// Succeeded reports whether no test failed, which is how helm test decides.
+func (s TestSummary) Succeeded() bool {
+	return s.Failed == 0
+}
+
+// RunReleaseTestSummary runs the tests of a release like RunReleaseTest, and
+// tallies all of the responses once the run is over.
+func (c *FakeClient) RunReleaseTestSummary(rlsName string, opts ...ReleaseTestOption) (TestSummary, error) {
+	results, errc := c.RunReleaseTest(rlsName, opts...)
+	summary := TestSummary{}
+	for res := range results {
+		switch res.Status {
+		case release.TestRun_SUCCESS:
+			summary.Passed++
+		case release.TestRun_FAILURE:
+			summary.Failed++
+		case release.TestRun_RUNNING:
+			summary.Running++
+		default:
+			summary.Unknown++
+		}
+		summary.Messages = append(summary.Messages, res.Msg)
+	}
+	return summary, <-errc
+}
+
 // PingTiller pings the Tiller pod and ensure's that it is up and running
 func (c *FakeClient) PingTiller() error {
+	if err := c.delay(); err != nil {
+		return err
+	}
+	c.record("PingTiller", "", "", nil)
 	return nil
 }
 
+// mockDeployedSeconds is the deployment date of mock releases, in seconds since the epoch.
+const mockDeployedSeconds = 242085845
+
 // MockHookTemplate is the hook template used for all mock release objects.
 var MockHookTemplate = `apiVersion: v1
 kind: Job
@@ -224,6 +985,26 @@ metadata:
   name: fixture
 `
 
+var (
+	mockRandMu sync.Mutex
+	// mockRand is the source of the names ReleaseMock generates for unnamed releases.
+	mockRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// SeedReleaseMock seeds the random source ReleaseMock uses to name releases
+// that have no name set, so that the generated names are reproducible.
+func SeedReleaseMock(seed int64) {
+	mockRandMu.Lock()
+	defer mockRandMu.Unlock()
+	mockRand = rand.New(rand.NewSource(seed))
+}
+
+func mockReleaseName() string {
+	mockRandMu.Lock()
+	defer mockRandMu.Unlock()
+	return fmt.Sprintf("testrelease-%d", mockRand.Intn(100))
+}
+
 // MockReleaseOptions allows for user-configurable options on mock release objects.
 type MockReleaseOptions struct {
 	Name        string
@@ -232,15 +1013,16 @@ type MockReleaseOptions struct {
 	StatusCode  release.Status_Code
 	Namespace   string
 	Description string
+	Config      *chart.Config
 }
 
 // ReleaseMock creates a mock release object based on options set by MockReleaseOptions. This function should typically not be used outside of testing.
 func ReleaseMock(opts *MockReleaseOptions) *release.Release {
-	date := timestamp.Timestamp{Seconds: 242085845, Nanos: 0}
+	date := timestamp.Timestamp{Seconds: mockDeployedSeconds, Nanos: 0}
 
 	name := opts.Name
 	if name == "" {
-		name = "testrelease-" + string(rand.Intn(100))
+		name = mockReleaseName()
 	}
 
 	var version int32 = 1
@@ -271,6 +1053,11 @@ func ReleaseMock(opts *MockReleaseOptions) *release.Release {
 		}
 	}
 
+	config := opts.Config
+	if config == nil {
+		config = &chart.Config{Raw: `name: "value"`}
+	}
+
 	scode := release.Status_DEPLOYED
 	if opts.StatusCode > 0 {
 		scode = opts.StatusCode
@@ -285,7 +1072,7 @@ func ReleaseMock(opts *MockReleaseOptions) *release.Release {
 			Description:   description,
 		},
 		Chart:     ch,
-		Config:    &chart.Config{Raw: `name: "value"`},
+		Config:    config,
 		Version:   version,
 		Namespace: namespace,
 		Hooks: []*release.Hook{
@@ -301,3 +1088,149 @@ func ReleaseMock(opts *MockReleaseOptions) *release.Release {
 		Manifest: MockManifest,
 	}
 }
+
+// RenderOption configures how RenderReleaseManifests and RenderReleaseMock
+// render a release.
+type RenderOption func(*renderOptions)
+
+type renderOptions struct {
+	apiVersions chartutil.VersionSet
+	kubeVersion string
+	strict      bool
+	render      RenderFunc
+}
+
+// RenderFunc renders the templates of a chart into files keyed by template path,
+// like renderutil.Render.
+type RenderFunc func(*chart.Chart, *chart.Config, renderutil.Options) (map[string]string, error)
+
+// RenderAPIVersions sets the API versions available to the templates, and
+// used to sort the rendered manifests. It defaults to chartutil.DefaultVersionSet.
+func RenderAPIVersions(versions chartutil.VersionSet) RenderOption {
+	return func(opts *renderOptions) {
+		opts.apiVersions = versions
+	}
+}
+
+// RenderKubeVersion sets the Kubernetes version (e.g. "1.10") available to the
+// templates. It defaults to chartutil.DefaultKubeVersion.
+func RenderKubeVersion(version string) RenderOption {
+	return func(opts *renderOptions) {
+		opts.kubeVersion = version
+	}
+}
+
+// RenderStrict makes rendering fail if a template references a missing value,
+// instead of rendering it empty.
+func RenderStrict(strict bool) RenderOption {
+	return func(opts *renderOptions) {
+		opts.strict = strict
+	}
+}
+
+// RenderWith replaces the function used to render the chart, e.g. to exercise
+// another engine or to simulate rendering failures. It defaults to renderutil.Render.
+func RenderWith(render RenderFunc) RenderOption {
+	return func(opts *renderOptions) {
+		opts.render = render
+	}
+}
+
+// RenderReleaseManifests renders the chart of a release (usually produced by
+// ReleaseMock) using the local renderer instead of Tiller, and returns the
+// resulting hooks and manifest. The release itself is left untouched.
+//
+// Compare to renderResources in pkg/tiller.
+func RenderReleaseManifests(r *release.Release, asUpgrade bool, opts ...RenderOption) ([]*release.Hook, string, error) {
+	hooks, rendered, _, err := renderRelease(r, asUpgrade, opts...)
+	return hooks, rendered, err
+}
+
+// renderRelease renders the chart of a release, including its dependencies, and
+// returns the hooks, manifest and notes. Unlike Tiller, which only keeps the notes
+// of the top-level chart, the notes of every subchart follow those of the chart,
+// ordered by path. Dependencies imported under an alias are rendered,
+// and so named, with their alias, as requirements are processed before rendering.
+func renderRelease(r *release.Release, asUpgrade bool, opts ...RenderOption) ([]*release.Hook, string, string, error) {
+	ro := renderOptions{apiVersions: chartutil.DefaultVersionSet, render: renderutil.Render}
+	for _, opt := range opts {
+		opt(&ro)
+	}
+
+	if r == nil || r.Chart == nil || r.Chart.Metadata == nil {
+		return nil, "", "", errors.New("a release with a chart with metadata must be provided to render the manifests")
+	}
+
+	// Processing requirements modifies the chart and its values, so render
+	// from copies.
+	ch := proto.Clone(r.Chart).(*chart.Chart)
+	var config *chart.Config
+	if r.Config != nil {
+		config = proto.Clone(r.Config).(*chart.Config)
+	}
+
+	renderOpts := renderutil.Options{
+		ReleaseOptions: chartutil.ReleaseOptions{
+			Name:      r.Name,
+			Namespace: r.Namespace,
+			Time:      r.GetInfo().GetLastDeployed(),
+			Revision:  int(r.Version),
+			IsUpgrade: asUpgrade,
+			IsInstall: !asUpgrade,
+		},
+		KubeVersion: ro.kubeVersion,
+		APIVersions: ro.apiVersions,
+		Strict:      ro.strict,
+	}
+	files, err := ro.render(ch, config, renderOpts)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	hooks, manifests, allNotes, err := manifest.Partition(files, ro.apiVersions, manifest.InstallOrder)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	b := bytes.NewBuffer(nil)
+	for _, m := range manifests {
+		b.WriteString("\n---\n# Source: " + m.Name + "\n")
+		b.WriteString(m.Content)
+	}
+
+	// The chart renders to <name>/templates, so its notes are keyed by its name
+	notes := []string{}
+	if n, ok := allNotes[ch.Metadata.Name]; ok {
+		notes = append(notes, n)
+	}
+	subcharts := []string{}
+	for chartPath := range allNotes {
+		if chartPath != ch.Metadata.Name {
+			subcharts = append(subcharts, chartPath)
+		}
+	}
+	sort.Strings(subcharts)
+	for _, chartPath := range subcharts {
+		notes = append(notes, allNotes[chartPath])
+	}
+	return hooks, b.String(), strings.Join(notes, "\n"), nil
+}
+
+// RenderReleaseMock renders the chart of a release like RenderReleaseManifests
+// and replaces the hooks, manifest and notes of the release with the result.
+func RenderReleaseMock(r *release.Release, asUpgrade bool, opts ...RenderOption) error {
+	hooks, rendered, notes, err := renderRelease(r, asUpgrade, opts...)
+	if err != nil {
+		return err
+	}
+	r.Hooks = hooks
+	r.Manifest = rendered
+	if r.Info == nil {
+		r.Info = &release.Info{}
+	}
+	if r.Info.Status == nil {
+		r.Info.Status = &release.Status{}
+	}
+	r.Info.Status.Notes = notes
+	return nil
+}
@@ -17,12 +17,25 @@ limitations under the License.
 package helm
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	rls "k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/renderutil"
 )
 
 func TestFakeClient_ReleaseStatus(t *testing.T) {
@@ -298,3 +311,1482 @@ func TestFakeClient_DeleteRelease(t *testing.T) {
 		})
 	}
 }
+
+func renderableRelease() *release.Release {
+	return ReleaseMock(&MockReleaseOptions{
+		Name: "renderable",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "renderable", Version: "0.1.0"},
+			Templates: []*chart.Template{
+				{Name: "templates/configmap.yaml", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+data:
+  name: {{ .Values.name }}
+`)},
+				{Name: "templates/hook.yaml", Data: []byte(`apiVersion: batch/v1
+kind: Job
+metadata:
+  name: {{ .Release.Name }}-hook
+  annotations:
+    "helm.sh/hook": post-install
+`)},
+				{Name: "templates/NOTES.txt", Data: []byte("notes for {{ .Release.Name }}")},
+			},
+			Values: &chart.Config{Raw: "name: default"},
+		},
+		Config: &chart.Config{Raw: "name: value"},
+	})
+}
+
+func TestRenderReleaseManifests(t *testing.T) {
+	rel := renderableRelease()
+	orig := proto.Clone(rel).(*release.Release)
+
+	hooks, manifest, err := RenderReleaseManifests(rel, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !proto.Equal(rel, orig) {
+		t.Errorf("Expected the release to be left untouched, got %v", rel)
+	}
+
+	if len(hooks) != 1 || hooks[0].Name != "renderable-hook" {
+		t.Errorf("Expected the renderable-hook hook, got %v", hooks)
+	}
+	if !reflect.DeepEqual(hooks[0].Events, []release.Hook_Event{release.Hook_POST_INSTALL}) {
+		t.Errorf("Expected a post-install hook, got %v", hooks[0].Events)
+	}
+	expect := "\n---\n# Source: renderable/templates/configmap.yaml\n" + `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: renderable-config
+data:
+  name: value`
+	if manifest != expect {
+		t.Errorf("Expected manifest %q, got %q", expect, manifest)
+	}
+
+	if _, _, err := RenderReleaseManifests(ReleaseMock(&MockReleaseOptions{Chart: &chart.Chart{}}), false); err == nil {
+		t.Error("Expected an error for a chart without metadata")
+	}
+}
+
+func TestRenderReleaseMock(t *testing.T) {
+	rel := renderableRelease()
+	hooks, manifest, err := RenderReleaseManifests(rel, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RenderReleaseMock(rel, true); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rel.Hooks, hooks) {
+		t.Errorf("Expected hooks %v, got %v", hooks, rel.Hooks)
+	}
+	if rel.Manifest != manifest {
+		t.Errorf("Expected manifest %q, got %q", manifest, rel.Manifest)
+	}
+}
+
+func TestRenderReleaseMockRenderWith(t *testing.T) {
+	rel := renderableRelease()
+	failing := func(*chart.Chart, *chart.Config, renderutil.Options) (map[string]string, error) {
+		return nil, errors.New("engine exploded")
+	}
+	if err := RenderReleaseMock(rel, false, RenderWith(failing)); err == nil || err.Error() != "engine exploded" {
+		t.Errorf("Expected the renderer error, got %v", err)
+	}
+
+	var gotOpts renderutil.Options
+	fixed := func(ch *chart.Chart, _ *chart.Config, opts renderutil.Options) (map[string]string, error) {
+		gotOpts = opts
+		return map[string]string{
+			ch.Metadata.Name + "/templates/cm.yaml":   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: fixed\n",
+			ch.Metadata.Name + "/templates/NOTES.txt": "fixed notes",
+		}, nil
+	}
+	if err := RenderReleaseMock(rel, true, RenderWith(fixed)); err != nil {
+		t.Fatal(err)
+	}
+	if !gotOpts.ReleaseOptions.IsUpgrade || gotOpts.ReleaseOptions.Name != rel.Name {
+		t.Errorf("Expected the release options to be passed to the renderer, got %+v", gotOpts.ReleaseOptions)
+	}
+	if !strings.Contains(rel.Manifest, "name: fixed") {
+		t.Errorf("Expected the fixed output in the manifest, got %q", rel.Manifest)
+	}
+	if rel.Info.Status.Notes != "fixed notes" {
+		t.Errorf("Expected the fixed notes, got %q", rel.Info.Status.Notes)
+	}
+}
+
+func TestRenderReleaseManifestsCapabilities(t *testing.T) {
+	rel := ReleaseMock(&MockReleaseOptions{
+		Name: "caps",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "caps", Version: "0.1.0"},
+			Templates: []*chart.Template{
+				{Name: "templates/cm.yaml", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: caps
+data:
+  minor: "{{ .Capabilities.KubeVersion.Minor }}"
+{{- if .Capabilities.APIVersions.Has "example.com/v1" }}
+  example: "yes"
+{{- end }}
+`)},
+			},
+		},
+	})
+
+	tests := []struct {
+		name   string
+		opts   []RenderOption
+		expect []string
+		reject []string
+	}{
+		{
+			name:   "defaults",
+			expect: []string{`minor: "9"`},
+			reject: []string{"example"},
+		},
+		{
+			name:   "custom capabilities",
+			opts:   []RenderOption{RenderAPIVersions(chartutil.NewVersionSet("v1", "example.com/v1")), RenderKubeVersion("1.10")},
+			expect: []string{`minor: "10"`, `example: "yes"`},
+		},
+	}
+
+	for _, tt := range tests {
+		_, manifest, err := RenderReleaseManifests(rel, false, tt.opts...)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		for _, e := range tt.expect {
+			if !strings.Contains(manifest, e) {
+				t.Errorf("%s: expected %q in manifest %q", tt.name, e, manifest)
+			}
+		}
+		for _, r := range tt.reject {
+			if strings.Contains(manifest, r) {
+				t.Errorf("%s: unexpected %q in manifest %q", tt.name, r, manifest)
+			}
+		}
+	}
+}
+
+func TestRenderReleaseManifestsStrict(t *testing.T) {
+	rel := ReleaseMock(&MockReleaseOptions{
+		Name: "strict",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "strict", Version: "0.1.0"},
+			Templates: []*chart.Template{
+				{Name: "templates/cm.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: strict\ndata:\n  missing: \"{{ .Values.missing }}\"\n")},
+			},
+		},
+	})
+
+	_, manifest, err := RenderReleaseManifests(rel, false)
+	if err != nil {
+		t.Fatalf("Expected lenient rendering to succeed, got %s", err)
+	}
+	if !strings.Contains(manifest, `missing: ""`) {
+		t.Errorf("Expected the missing value to render empty, got %q", manifest)
+	}
+
+	_, _, err = RenderReleaseManifests(rel, false, RenderStrict(true))
+	if err == nil {
+		t.Fatal("Expected strict rendering to fail")
+	}
+	if !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Expected the error to name the missing key, got %s", err)
+	}
+}
+
+func TestFakeClient_ListReleasesNamespaces(t *testing.T) {
+	c := &FakeClient{
+		Rels: []*release.Release{
+			ReleaseMock(&MockReleaseOptions{Name: "one", Namespace: "default"}),
+			ReleaseMock(&MockReleaseOptions{Name: "two", Namespace: "other"}),
+			ReleaseMock(&MockReleaseOptions{Name: "three", Namespace: "other"}),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		opts   []ReleaseListOption
+		expect []string
+	}{
+		{"no namespace", nil, []string{"one", "two", "three"}},
+		{"all namespaces", []ReleaseListOption{ReleaseListAllNamespaces()}, []string{"one", "two", "three"}},
+		{"specific namespace", []ReleaseListOption{ReleaseListNamespace("other")}, []string{"two", "three"}},
+		{"all namespaces overrides namespace", []ReleaseListOption{ReleaseListNamespace("other"), ReleaseListAllNamespaces()}, []string{"one", "two", "three"}},
+		{"namespace overrides all namespaces", []ReleaseListOption{ReleaseListAllNamespaces(), ReleaseListNamespace("other")}, []string{"two", "three"}},
+		{"unknown namespace", []ReleaseListOption{ReleaseListNamespace("nope")}, []string{}},
+		{"limit within namespace", []ReleaseListOption{ReleaseListNamespace("other"), ReleaseListLimit(1)}, []string{"two"}},
+	}
+
+	for _, tt := range tests {
+		resp, err := c.ListReleases(tt.opts...)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		names := []string{}
+		for _, rel := range resp.Releases {
+			names = append(names, rel.Name)
+		}
+		if !reflect.DeepEqual(names, tt.expect) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expect, names)
+		}
+		if resp.Count != int64(len(tt.expect)) {
+			t.Errorf("%s: expected count %d, got %d", tt.name, len(tt.expect), resp.Count)
+		}
+	}
+}
+
+func TestSeedReleaseMock(t *testing.T) {
+	names := func() []string {
+		SeedReleaseMock(42)
+		var n []string
+		for i := 0; i < 5; i++ {
+			n = append(n, ReleaseMock(&MockReleaseOptions{}).Name)
+		}
+		return n
+	}
+
+	first, second := names(), names()
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same names for the same seed, got %v and %v", first, second)
+	}
+	for _, name := range first {
+		if !strings.HasPrefix(name, "testrelease-") {
+			t.Errorf("Unexpected generated name %q", name)
+		}
+	}
+}
+
+func TestFakeClient_InstallReleaseGenerateNames(t *testing.T) {
+	valid := regexp.MustCompile("^[a-z0-9]([-a-z0-9]*[a-z0-9])?$")
+
+	// The first name generated for the seed is already in use
+	SeedReleaseMock(7)
+	taken := ReleaseMock(&MockReleaseOptions{}).Name
+	SeedReleaseMock(7)
+
+	c := &FakeClient{
+		Rels:          []*release.Release{ReleaseMock(&MockReleaseOptions{Name: taken})},
+		GenerateNames: true,
+	}
+	names := map[string]bool{taken: true}
+	for i := 0; i < 2; i++ {
+		resp, err := c.InstallReleaseFromChart(&chart.Chart{}, "default")
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := resp.Release.Name
+		if !valid.MatchString(name) || len(name) > 53 {
+			t.Errorf("Expected a valid release name, got %q", name)
+		}
+		if names[name] {
+			t.Errorf("Expected a name that is not in use, got %q", name)
+		}
+		names[name] = true
+	}
+
+	c = &FakeClient{}
+	resp, err := c.InstallReleaseFromChart(&chart.Chart{}, "default", ReleaseName("named"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Release.Name != "named" {
+		t.Errorf("Expected the requested name, got %q", resp.Release.Name)
+	}
+}
+
+func TestFakeClient_ReleaseContentRenderManifests(t *testing.T) {
+	stored := renderableRelease()
+	stored.Manifest = ""
+	stored.Hooks = nil
+
+	c := &FakeClient{Rels: []*release.Release{stored}}
+	resp, err := c.ReleaseContent("renderable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Release.Manifest != "" {
+		t.Errorf("Expected no rendering without RenderManifests, got %q", resp.Release.Manifest)
+	}
+
+	c.RenderManifests = true
+	resp, err = c.ReleaseContent("renderable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(resp.Release.Manifest, "name: renderable-config") {
+		t.Errorf("Expected a rendered manifest, got %q", resp.Release.Manifest)
+	}
+	if len(resp.Release.Hooks) != 1 {
+		t.Errorf("Expected 1 rendered hook, got %d", len(resp.Release.Hooks))
+	}
+	if stored.Manifest != "" {
+		t.Errorf("Expected the stored release to be left untouched, got %q", stored.Manifest)
+	}
+}
+
+func TestFakeClient_Delay(t *testing.T) {
+	c := &FakeClient{Delay: 200 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.ListReleases()
+		done <- err
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Expected the call to outlast the deadline")
+	case <-ctx.Done():
+		if ctx.Err() != context.DeadlineExceeded {
+			t.Errorf("Expected %v, got %v", context.DeadlineExceeded, ctx.Err())
+		}
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("Expected the delayed call to succeed, got %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < c.Delay {
+		t.Errorf("Expected the call to take at least %v, took %v", c.Delay, elapsed)
+	}
+}
+
+func TestFakeClient_DelayCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	c := &FakeClient{
+		Rels:    []*release.Release{ReleaseMock(&MockReleaseOptions{Name: "slow"})},
+		Delay:   time.Minute,
+		Context: ctx,
+	}
+
+	start := time.Now()
+	if _, err := c.ReleaseStatus("slow"); err != context.DeadlineExceeded {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed >= c.Delay {
+		t.Errorf("Expected the call to return once the deadline passed, took %v", elapsed)
+	}
+	if len(c.Calls) != 0 {
+		t.Errorf("Expected the cancelled call not to be made, got %v", c.Calls)
+	}
+
+	// Calls on a client whose context is already done fail right away
+	if err := c.PingTiller(); err != context.DeadlineExceeded {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if _, errc := c.RunReleaseTest("slow"); <-errc != context.DeadlineExceeded {
+		t.Error("Expected the release test to fail with the context error")
+	}
+}
+
+func TestFakeClient_DeleteReleasesBySelector(t *testing.T) {
+	c := &FakeClient{
+		Rels: []*release.Release{
+			ReleaseMock(&MockReleaseOptions{Name: "web"}),
+			ReleaseMock(&MockReleaseOptions{Name: "worker"}),
+			ReleaseMock(&MockReleaseOptions{Name: "db"}),
+		},
+		Labels: map[string]map[string]string{
+			"web":    {"team": "frontend", "env": "test"},
+			"worker": {"team": "frontend"},
+			"db":     {"team": "storage", "env": "test"},
+		},
+	}
+
+	deleted, err := c.DeleteReleasesBySelector(map[string]string{"team": "frontend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, rel := range deleted {
+		names = append(names, rel.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"web", "worker"}) {
+		t.Errorf("Expected web and worker to be deleted, got %v", names)
+	}
+	if len(c.Rels) != 1 || c.Rels[0].Name != "db" {
+		t.Errorf("Expected only db to remain, got %v", c.Rels)
+	}
+
+	if _, err := c.DeleteReleasesBySelector(nil); err == nil {
+		t.Error("Expected an error for an empty selector")
+	}
+}
+
+func TestFakeClient_DeleteReleasesBySelectorRevisions(t *testing.T) {
+	c := &FakeClient{
+		Rels: []*release.Release{
+			ReleaseMock(&MockReleaseOptions{Name: "web", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+			ReleaseMock(&MockReleaseOptions{Name: "db"}),
+			ReleaseMock(&MockReleaseOptions{Name: "web", Version: 2, StatusCode: release.Status_SUPERSEDED}),
+			ReleaseMock(&MockReleaseOptions{Name: "web", Version: 3}),
+		},
+		Labels: map[string]map[string]string{"web": {"team": "frontend"}},
+	}
+
+	deleted, err := c.DeleteReleasesBySelector(map[string]string{"team": "frontend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0].Name != "web" || deleted[0].Version != 3 {
+		t.Errorf("Expected web to be deleted once, by its latest revision, got %v", deleted)
+	}
+	if len(c.Rels) != 1 || c.Rels[0].Name != "db" {
+		t.Errorf("Expected every revision of web to be removed, got %v", c.Rels)
+	}
+	deletes := 0
+	for _, call := range c.Calls {
+		if call.Method == "DeleteRelease" {
+			deletes++
+		}
+	}
+	if deletes != 1 {
+		t.Errorf("Expected a single DeleteRelease call, got %d", deletes)
+	}
+}
+
+func TestFakeClient_ListReleasesChart(t *testing.T) {
+	other := &chart.Chart{Metadata: &chart.Metadata{Name: "other", Version: "1.0.0"}}
+	c := &FakeClient{
+		Rels: []*release.Release{
+			ReleaseMock(&MockReleaseOptions{Name: "one"}),
+			ReleaseMock(&MockReleaseOptions{Name: "two", Chart: other}),
+			ReleaseMock(&MockReleaseOptions{Name: "three"}),
+		},
+	}
+
+	tests := []struct {
+		chart  string
+		expect []string
+	}{
+		{"foo", []string{"one", "three"}},
+		{"other", []string{"two"}},
+		{"missing", []string{}},
+	}
+
+	for _, tt := range tests {
+		resp, err := c.ListReleases(ReleaseListChart(tt.chart))
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := []string{}
+		for _, rel := range resp.Releases {
+			names = append(names, rel.Name)
+		}
+		if !reflect.DeepEqual(names, tt.expect) {
+			t.Errorf("%s: expected %v, got %v", tt.chart, tt.expect, names)
+		}
+	}
+}
+
+func TestFakeClient_RollbackRelease(t *testing.T) {
+	failed := ReleaseMock(&MockReleaseOptions{Name: "funny-honey", Version: 2, StatusCode: release.Status_FAILED})
+	failed.Manifest = ""
+	c := &FakeClient{
+		Rels: []*release.Release{
+			ReleaseMock(&MockReleaseOptions{Name: "funny-honey", Version: 4}),
+			ReleaseMock(&MockReleaseOptions{Name: "funny-honey", Version: 3, StatusCode: release.Status_DELETED}),
+			failed,
+			ReleaseMock(&MockReleaseOptions{Name: "funny-honey", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+		},
+	}
+
+	tests := []struct {
+		name    string
+		version int32
+		err     string
+	}{
+		{"current revision", 4, "already at revision 4"},
+		{"previous revision is deleted", 0, "revision 3 is DELETED"},
+		{"failed revision without manifest", 2, "revision 2 is FAILED"},
+		{"missing revision", 7, "revision 7 not found"},
+		{"valid older revision", 1, ""},
+	}
+
+	for _, tt := range tests {
+		resp, err := c.RollbackRelease("funny-honey", RollbackVersion(tt.version))
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.name, err)
+			continue
+		}
+		if resp.Release.Version != 4 {
+			t.Errorf("%s: expected current release to be returned, got revision %d", tt.name, resp.Release.Version)
+		}
+	}
+}
+
+func TestFakeClient_RollbackReleaseDryRun(t *testing.T) {
+	current := ReleaseMock(&MockReleaseOptions{Name: "funny-honey", Version: 2})
+	deployed := current.Info.LastDeployed
+	c := &FakeClient{
+		Rels: []*release.Release{
+			current,
+			ReleaseMock(&MockReleaseOptions{Name: "funny-honey", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+		},
+	}
+
+	if _, err := c.RollbackRelease("funny-honey", RollbackVersion(1), RollbackDryRun(true), RollbackForce(true)); err != nil {
+		t.Fatal(err)
+	}
+	req, ok := c.Calls[0].Request.(*rls.RollbackReleaseRequest)
+	if !ok || !req.DryRun || !req.Force {
+		t.Errorf("Expected the dry run and force options in the request, got %v", c.Calls[0].Request)
+	}
+	if !proto.Equal(current.Info.LastDeployed, deployed) {
+		t.Errorf("Expected a dry run to leave the release untouched, got last deployed %v", current.Info.LastDeployed)
+	}
+}
+
+func TestFakeClient_MaxHistory(t *testing.T) {
+	rel := renderableRelease()
+	c := &FakeClient{
+		Rels:            []*release.Release{rel},
+		RenderManifests: true,
+		MaxHistory:      3,
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := c.UpdateReleaseFromChart(rel.Name, rel.Chart); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(c.Rels) != 3 {
+		t.Fatalf("Expected 3 revisions, got %d", len(c.Rels))
+	}
+	for i, r := range c.Rels {
+		if expected := int32(i + 4); r.Version != expected {
+			t.Errorf("Expected revision %d, got %d", expected, r.Version)
+		}
+	}
+	if code := c.Rels[2].Info.Status.Code; code != release.Status_DEPLOYED {
+		t.Errorf("Expected latest revision to be DEPLOYED, got %s", code)
+	}
+}
+
+func TestFakeClient_UpdateReleaseVersionGaps(t *testing.T) {
+	c := &FakeClient{RenderManifests: true}
+	for _, version := range []int32{1, 5, 3} {
+		rel := renderableRelease()
+		rel.Version = version
+		rel.Info.Status.Code = release.Status_SUPERSEDED
+		c.Rels = append(c.Rels, rel)
+	}
+
+	resp, err := c.UpdateReleaseFromChart("renderable", c.Rels[0].Chart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Release.Version != 6 {
+		t.Errorf("Expected revision 6, got %d", resp.Release.Version)
+	}
+
+	ch := c.Rels[0].Chart
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.UpdateReleaseFromChart("renderable", ch); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := map[int32]bool{}
+	for _, rel := range c.Rels {
+		if seen[rel.Version] {
+			t.Errorf("Expected revision %d to be assigned once", rel.Version)
+		}
+		seen[rel.Version] = true
+	}
+	if !seen[11] {
+		t.Errorf("Expected concurrent upgrades to reach revision 11, got %v", seen)
+	}
+}
+
+func TestFakeClient_Calls(t *testing.T) {
+	c := &FakeClient{}
+
+	if _, err := c.InstallReleaseFromChart(&chart.Chart{}, "tea", ReleaseName("new-release"), InstallDescription("first")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UpdateRelease("new-release", "chart", UpgradeDryRun(true)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ReleaseStatus("new-release", StatusReleaseVersion(1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.DeleteRelease("new-release", DeletePurge(true)); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []FakeCall{
+		{
+			Method:    "InstallReleaseFromChart",
+			Release:   "new-release",
+			Namespace: "tea",
+			Request:   &rls.InstallReleaseRequest{Name: "new-release", Namespace: "tea", Description: "first"},
+		},
+		{
+			Method:  "UpdateRelease",
+			Release: "new-release",
+			Request: &rls.UpdateReleaseRequest{Name: "new-release", DryRun: true},
+		},
+		{
+			Method:  "ReleaseStatus",
+			Release: "new-release",
+			Request: &rls.GetReleaseStatusRequest{Name: "new-release", Version: 1},
+		},
+		{
+			Method:  "DeleteRelease",
+			Release: "new-release",
+			Request: &rls.UninstallReleaseRequest{Name: "new-release", Purge: true},
+		},
+	}
+
+	if len(c.Calls) != len(expect) {
+		t.Fatalf("Expected %d calls, got %d: %v", len(expect), len(c.Calls), c.Calls)
+	}
+	for i, call := range c.Calls {
+		e := expect[i]
+		if call.Method != e.Method || call.Release != e.Release || call.Namespace != e.Namespace {
+			t.Errorf("Expected call %d to be %s(%q, %q), got %s(%q, %q)", i, e.Method, e.Release, e.Namespace, call.Method, call.Release, call.Namespace)
+		}
+		if !proto.Equal(call.Request, e.Request) {
+			t.Errorf("Expected %s request %v, got %v", e.Method, e.Request, call.Request)
+		}
+	}
+
+	c.ResetCalls()
+	if len(c.Calls) != 0 {
+		t.Errorf("Expected no calls after ResetCalls, got %v", c.Calls)
+	}
+}
+
+func TestFakeClient_InstallReleaseFromChartDryRun(t *testing.T) {
+	existing := ReleaseMock(&MockReleaseOptions{Name: "existing"})
+	c := &FakeClient{
+		Rels:            []*release.Release{existing},
+		RenderManifests: true,
+	}
+
+	resp, err := c.InstallReleaseFromChart(renderableRelease().Chart, "default", ReleaseName("preview"), InstallDryRun(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(resp.Release.Manifest, "kind: ConfigMap") {
+		t.Errorf("Expected a rendered manifest, got %q", resp.Release.Manifest)
+	}
+	if len(resp.Release.Hooks) != 1 || resp.Release.Hooks[0].Kind != "Job" {
+		t.Errorf("Expected the rendered hook, got %v", resp.Release.Hooks)
+	}
+	if len(c.Rels) != 1 || c.Rels[0] != existing {
+		t.Errorf("Expected the dry run not to add a release, got %v", c.Rels)
+	}
+	if req, ok := c.Calls[0].Request.(*rls.InstallReleaseRequest); !ok || !req.DryRun {
+		t.Errorf("Expected a dry run request to be recorded, got %v", c.Calls[0].Request)
+	}
+
+	// The options of the dry run do not carry over to the next install
+	if _, err := c.InstallReleaseFromChart(renderableRelease().Chart, "default", ReleaseName("real")); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Rels) != 2 || c.Rels[1].Name != "real" {
+		t.Errorf("Expected the next install to be stored, got %v", c.Rels)
+	}
+}
+
+func TestRenderReleaseMockDependencies(t *testing.T) {
+	rel := ReleaseMock(&MockReleaseOptions{
+		Name: "parent",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "parent", Version: "0.1.0"},
+			Templates: []*chart.Template{
+				{Name: "templates/NOTES.txt", Data: []byte("parent notes")},
+			},
+			Dependencies: []*chart.Chart{
+				{
+					Metadata: &chart.Metadata{Name: "child", Version: "0.1.0"},
+					Templates: []*chart.Template{
+						{Name: "templates/configmap.yaml", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-child
+`)},
+						{Name: "templates/NOTES.txt", Data: []byte("child notes")},
+					},
+				},
+			},
+		},
+	})
+
+	if err := RenderReleaseMock(rel, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(rel.Manifest, "# Source: parent/charts/child/templates/configmap.yaml") {
+		t.Errorf("Expected the subchart manifest to be rendered, got %q", rel.Manifest)
+	}
+	if !strings.Contains(rel.Manifest, "name: parent-child") {
+		t.Errorf("Expected the subchart to be rendered with the release name, got %q", rel.Manifest)
+	}
+	if expect := "parent notes\nchild notes"; rel.Info.Status.Notes != expect {
+		t.Errorf("Expected notes %q, got %q", expect, rel.Info.Status.Notes)
+	}
+}
+
+func TestRenderReleaseMockAliasedDependencies(t *testing.T) {
+	child := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "child", Version: "0.1.0"},
+		Templates: []*chart.Template{
+			{Name: "templates/NOTES.txt", Data: []byte("{{ .Chart.Name }} notes")},
+		},
+	}
+	rel := ReleaseMock(&MockReleaseOptions{
+		Name: "parent",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "parent", Version: "0.1.0"},
+			Templates: []*chart.Template{
+				{Name: "templates/NOTES.txt", Data: []byte("parent notes")},
+			},
+			Files: []*any.Any{
+				{TypeUrl: "requirements.yaml", Value: []byte("dependencies:\n- name: child\n  version: 0.1.0\n  alias: zz\n- name: child\n  version: 0.1.0\n  alias: aa\n")},
+			},
+			Dependencies: []*chart.Chart{child},
+		},
+	})
+
+	if err := RenderReleaseMock(rel, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Subchart notes follow in order of their name, which for aliased dependencies is the alias
+	if expect := "parent notes\naa notes\nzz notes"; rel.Info.Status.Notes != expect {
+		t.Errorf("Expected notes %q, got %q", expect, rel.Info.Status.Notes)
+	}
+	if rel.Chart.Dependencies[0].Metadata.Name != "child" {
+		t.Errorf("Expected the chart of the release not to be modified, got %v", rel.Chart.Dependencies)
+	}
+}
+
+func TestFakeClient_ListReleasesIncludeDeleted(t *testing.T) {
+	c := &FakeClient{
+		Rels: []*release.Release{
+			ReleaseMock(&MockReleaseOptions{Name: "deployed"}),
+			ReleaseMock(&MockReleaseOptions{Name: "deleted", StatusCode: release.Status_DELETED}),
+			ReleaseMock(&MockReleaseOptions{Name: "also-deployed"}),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		opts   []ReleaseListOption
+		expect []string
+	}{
+		{"default", nil, []string{"deployed", "also-deployed"}},
+		{"excluded", []ReleaseListOption{ReleaseListIncludeDeleted(false)}, []string{"deployed", "also-deployed"}},
+		{"included", []ReleaseListOption{ReleaseListIncludeDeleted(true)}, []string{"deployed", "deleted", "also-deployed"}},
+		{"requested by status", []ReleaseListOption{ReleaseListStatuses([]release.Status_Code{release.Status_DELETED})}, []string{"deployed", "deleted", "also-deployed"}},
+	}
+
+	for _, tt := range tests {
+		resp, err := c.ListReleases(tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := []string{}
+		for _, rel := range resp.Releases {
+			names = append(names, rel.Name)
+		}
+		if !reflect.DeepEqual(names, tt.expect) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expect, names)
+		}
+	}
+}
+
+func TestFakeClient_ListReleasesPagination(t *testing.T) {
+	c := &FakeClient{}
+	expect := []string{}
+	for i := 0; i < 7; i++ {
+		name := fmt.Sprintf("release-%d", i)
+		c.Rels = append(c.Rels, ReleaseMock(&MockReleaseOptions{Name: name}))
+		expect = append(expect, name)
+	}
+
+	names := []string{}
+	pages := 0
+	offset := ""
+	for {
+		resp, err := c.ListReleases(ReleaseListLimit(3), ReleaseListOffset(offset))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		if pages > len(c.Rels) {
+			t.Fatal("Expected pagination to terminate")
+		}
+		if resp.Total != int64(len(c.Rels)) {
+			t.Errorf("Expected a total of %d, got %d", len(c.Rels), resp.Total)
+		}
+		if resp.Count != int64(len(resp.Releases)) {
+			t.Errorf("Expected count %d, got %d", len(resp.Releases), resp.Count)
+		}
+		for _, rel := range resp.Releases {
+			names = append(names, rel.Name)
+		}
+		if resp.Next == "" {
+			break
+		}
+		offset = resp.Next
+	}
+
+	if pages != 3 {
+		t.Errorf("Expected 3 pages, got %d", pages)
+	}
+	if !reflect.DeepEqual(names, expect) {
+		t.Errorf("Expected %v, got %v", expect, names)
+	}
+
+	if _, err := c.ListReleases(ReleaseListOffset("missing")); err == nil {
+		t.Error("Expected an error for an unknown offset")
+	}
+}
+
+func TestFakeClient_ListReleasesPaginationRevisions(t *testing.T) {
+	c := &FakeClient{
+		Rels: []*release.Release{
+			ReleaseMock(&MockReleaseOptions{Name: "a", Version: 1}),
+			ReleaseMock(&MockReleaseOptions{Name: "a", Version: 2}),
+			ReleaseMock(&MockReleaseOptions{Name: "b", Version: 1}),
+		},
+	}
+
+	got := []*release.Release{}
+	offset := ""
+	for pages := 0; ; pages++ {
+		if pages > len(c.Rels) {
+			t.Fatal("Expected pagination to terminate")
+		}
+		resp, err := c.ListReleases(ReleaseListLimit(1), ReleaseListOffset(offset))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Total != 2 {
+			t.Errorf("Expected a total of 2, got %d", resp.Total)
+		}
+		got = append(got, resp.Releases...)
+		if resp.Next == "" {
+			break
+		}
+		offset = resp.Next
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Expected one release per name, got %d", len(got))
+	}
+	if got[0].Name != "a" || got[0].Version != 2 {
+		t.Errorf("Expected the latest revision of a, got %s.v%d", got[0].Name, got[0].Version)
+	}
+	if got[1].Name != "b" {
+		t.Errorf("Expected b, got %s", got[1].Name)
+	}
+}
+
+func TestFakeClient_ReleaseStatusResources(t *testing.T) {
+	table := "==> v1/ConfigMap\nNAME        DATA  AGE\nwith-table  1     1m\n"
+	c := &FakeClient{
+		Rels: []*release.Release{
+			ReleaseMock(&MockReleaseOptions{Name: "with-table"}),
+			ReleaseMock(&MockReleaseOptions{Name: "without-table"}),
+		},
+		Resources: map[string]string{"with-table": table},
+	}
+
+	status, err := c.ReleaseStatus("with-table")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := status.Info.Status.Resources; got != table {
+		t.Errorf("Expected resources %q, got %q", table, got)
+	}
+	if c.Rels[0].Info.Status.Resources != "" {
+		t.Error("Expected the stored release to be left untouched")
+	}
+
+	status, err = c.ReleaseStatus("without-table")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := status.Info.Status.Resources; got != "" {
+		t.Errorf("Expected no resources, got %q", got)
+	}
+}
+
+func TestFakeClient_SetReleaseStatus(t *testing.T) {
+	c := &FakeClient{
+		Rels: []*release.Release{
+			ReleaseMock(&MockReleaseOptions{Name: "stuck", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+			ReleaseMock(&MockReleaseOptions{Name: "stuck", Version: 2}),
+		},
+	}
+
+	if err := c.SetReleaseStatus("stuck", 2, release.Status_PENDING_UPGRADE); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := c.ReleaseStatus("stuck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := status.Info.Status.Code; code != release.Status_PENDING_UPGRADE {
+		t.Errorf("Expected status PENDING_UPGRADE, got %s", code)
+	}
+
+	list, err := c.ListReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range list.Releases {
+		expect := release.Status_SUPERSEDED
+		if rel.Version == 2 {
+			expect = release.Status_PENDING_UPGRADE
+		}
+		if code := rel.Info.Status.Code; code != expect {
+			t.Errorf("Expected revision %d to be %s, got %s", rel.Version, expect, code)
+		}
+	}
+
+	if err := c.SetReleaseStatus("stuck", 3, release.Status_FAILED); err == nil {
+		t.Error("Expected an error for a missing revision")
+	}
+}
+
+func TestFakeClient_GetRevision(t *testing.T) {
+	c := &FakeClient{
+		Rels: []*release.Release{
+			ReleaseMock(&MockReleaseOptions{Name: "rev", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+			ReleaseMock(&MockReleaseOptions{Name: "rev", Version: 2}),
+		},
+	}
+
+	rel, err := c.GetRevision("rev", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel != c.Rels[0] {
+		t.Errorf("Expected the stored revision 1, got %v", rel)
+	}
+
+	rel, err = c.GetRevision("rev", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.Version != 2 {
+		t.Errorf("Expected the latest revision, got %d", rel.Version)
+	}
+
+	if _, err := c.GetRevision("rev", 3); err == nil {
+		t.Error("Expected an error for a missing revision")
+	}
+	if _, err := c.GetRevision("missing", 1); err == nil {
+		t.Error("Expected an error for a missing release")
+	}
+}
+
+func TestFakeClient_SeedReleases(t *testing.T) {
+	c := &FakeClient{}
+
+	err := c.SeedReleases(
+		ReleaseMock(&MockReleaseOptions{Name: "seeded", Version: 2, StatusCode: release.Status_SUPERSEDED}),
+		ReleaseMock(&MockReleaseOptions{Name: "seeded", Version: 3}),
+		ReleaseMock(&MockReleaseOptions{Name: "other", Version: 1}),
+		ReleaseMock(&MockReleaseOptions{Name: "seeded", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.ReleaseHistory("seeded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions := []int32{}
+	for _, rel := range resp.Releases {
+		versions = append(versions, rel.Version)
+	}
+	if expect := []int32{3, 2, 1}; !reflect.DeepEqual(versions, expect) {
+		t.Errorf("Expected revisions %v, got %v", expect, versions)
+	}
+
+	resp, err = c.ReleaseHistory("seeded", WithMaxHistory(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Releases) != 1 || resp.Releases[0].Version != 3 {
+		t.Errorf("Expected only the latest revision, got %v", resp.Releases)
+	}
+
+	duplicate := ReleaseMock(&MockReleaseOptions{Name: "seeded", Version: 3})
+	if err := c.SeedReleases(duplicate); err == nil {
+		t.Error("Expected an error seeding an existing revision")
+	}
+	if len(c.Rels) != 4 {
+		t.Errorf("Expected a failed seed not to store anything, got %d releases", len(c.Rels))
+	}
+	c.ForceSeedReleases(duplicate)
+	if len(c.Rels) != 5 {
+		t.Errorf("Expected a forced seed to be stored, got %d releases", len(c.Rels))
+	}
+
+	if _, err := c.ReleaseHistory("missing"); err == nil {
+		t.Error("Expected an error for the history of a missing release")
+	}
+}
+
+func TestFakeClient_RunReleaseTestTimeout(t *testing.T) {
+	c := &FakeClient{
+		Responses: map[string]release.TestRun_Status{
+			"PASSED: quick": release.TestRun_SUCCESS,
+			"PASSED: slow":  release.TestRun_SUCCESS,
+		},
+		TestDurations: map[string]time.Duration{
+			"PASSED: quick": time.Second,
+			"PASSED: slow":  time.Minute,
+		},
+	}
+
+	run := func(opts ...ReleaseTestOption) map[string]release.TestRun_Status {
+		results, errc := c.RunReleaseTest("tested", opts...)
+		got := map[string]release.TestRun_Status{}
+		for res := range results {
+			got[res.Msg] = res.Status
+		}
+		if err := <-errc; err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	expect := map[string]release.TestRun_Status{
+		"PASSED: quick": release.TestRun_SUCCESS,
+		"ERROR: PASSED: slow timed out after 30s": release.TestRun_FAILURE,
+	}
+	if got := run(ReleaseTestTimeout(30)); !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected %v, got %v", expect, got)
+	}
+
+	if got := run(ReleaseTestTimeout(120)); !reflect.DeepEqual(got, c.Responses) {
+		t.Errorf("Expected every test to finish within the timeout, got %v", got)
+	}
+	if got := run(); !reflect.DeepEqual(got, c.Responses) {
+		t.Errorf("Expected no timeout without ReleaseTestTimeout, got %v", got)
+	}
+}
+
+func TestFakeClient_RunReleaseTestDeletesFailedHooks(t *testing.T) {
+	newRelease := func() *release.Release {
+		rel := ReleaseMock(&MockReleaseOptions{Name: "tested"})
+		rel.Hooks = []*release.Hook{
+			{Name: "pre-install", Events: []release.Hook_Event{release.Hook_PRE_INSTALL}, DeletePolicies: []release.Hook_DeletePolicy{release.Hook_FAILED}},
+			{Name: "test-kept", Events: []release.Hook_Event{release.Hook_RELEASE_TEST_SUCCESS}, DeletePolicies: []release.Hook_DeletePolicy{release.Hook_SUCCEEDED}},
+			{Name: "test-deleted", Events: []release.Hook_Event{release.Hook_RELEASE_TEST_FAILURE}, DeletePolicies: []release.Hook_DeletePolicy{release.Hook_SUCCEEDED, release.Hook_FAILED}},
+		}
+		return rel
+	}
+	hookNames := func(rel *release.Release) []string {
+		names := []string{}
+		for _, h := range rel.Hooks {
+			names = append(names, h.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name      string
+		responses map[string]release.TestRun_Status
+		expect    []string
+	}{
+		{
+			name:      "passing tests",
+			responses: map[string]release.TestRun_Status{"PASSED: test-deleted": release.TestRun_SUCCESS},
+			expect:    []string{"pre-install", "test-kept", "test-deleted"},
+		},
+		{
+			name: "failing test",
+			responses: map[string]release.TestRun_Status{
+				"PASSED: test-kept":    release.TestRun_SUCCESS,
+				"FAILED: test-deleted": release.TestRun_FAILURE,
+			},
+			expect: []string{"pre-install", "test-kept"},
+		},
+	}
+
+	for _, tt := range tests {
+		rel := newRelease()
+		c := &FakeClient{Rels: []*release.Release{rel}, Responses: tt.responses}
+		if _, err := c.RunReleaseTestSummary("tested"); err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if got := hookNames(rel); !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("%s: expected hooks %v, got %v", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestFakeClient_UpdateReleaseRemovedHooks(t *testing.T) {
+	rel := renderableRelease()
+	c := &FakeClient{Rels: []*release.Release{rel}}
+
+	withHook := proto.Clone(rel.Chart).(*chart.Chart)
+	resp, err := c.UpdateReleaseFromChart(rel.Name, withHook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Release.Hooks) != 1 || resp.Release.Hooks[0].Name != "renderable-hook" {
+		t.Fatalf("Expected the hook of the chart instead of the mock one, got %v", resp.Release.Hooks)
+	}
+
+	withoutHook := proto.Clone(withHook).(*chart.Chart)
+	templates := []*chart.Template{}
+	for _, tpl := range withoutHook.Templates {
+		if tpl.Name != "templates/hook.yaml" {
+			templates = append(templates, tpl)
+		}
+	}
+	withoutHook.Templates = templates
+
+	resp, err = c.UpdateReleaseFromChart(rel.Name, withoutHook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Release.Hooks) != 0 {
+		t.Errorf("Expected the removed hook not to persist, got %v", resp.Release.Hooks)
+	}
+	if resp.Release.Chart != withoutHook {
+		t.Error("Expected the release to use the new chart")
+	}
+}
+
+func TestFakeClient_UpdateReleaseRequireDeployed(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    release.Status_Code
+		force   bool
+		wantErr bool
+	}{
+		{"deployed", release.Status_DEPLOYED, false, false},
+		{"failed without force", release.Status_FAILED, false, true},
+		{"failed with force", release.Status_FAILED, true, false},
+		{"pending without force", release.Status_PENDING_UPGRADE, false, true},
+		{"pending with force", release.Status_PENDING_UPGRADE, true, false},
+	}
+
+	for _, tt := range tests {
+		rel := renderableRelease()
+		rel.Info.Status.Code = tt.code
+		c := &FakeClient{
+			Rels:            []*release.Release{rel},
+			RenderManifests: true,
+			RequireDeployed: true,
+		}
+
+		resp, err := c.UpdateReleaseFromChart(rel.Name, rel.Chart, UpgradeForce(tt.force))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %t, got %v", tt.name, tt.wantErr, err)
+		}
+		if err != nil {
+			if len(c.Rels) != 1 || rel.Info.Status.Code != tt.code {
+				t.Errorf("%s: expected the rejected upgrade to leave the release alone", tt.name)
+			}
+			continue
+		}
+		if resp.Release.Version != 2 || resp.Release.Info.Status.Code != release.Status_DEPLOYED {
+			t.Errorf("%s: expected a DEPLOYED revision 2, got revision %d %s", tt.name, resp.Release.Version, resp.Release.Info.Status.Code)
+		}
+	}
+}
+
+func TestFakeClient_Deployed(t *testing.T) {
+	c := &FakeClient{
+		Rels: []*release.Release{
+			ReleaseMock(&MockReleaseOptions{Name: "healthy", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+			ReleaseMock(&MockReleaseOptions{Name: "healthy", Version: 2}),
+			ReleaseMock(&MockReleaseOptions{Name: "healthy", Version: 3, StatusCode: release.Status_FAILED}),
+			ReleaseMock(&MockReleaseOptions{Name: "undeployed", Version: 1, StatusCode: release.Status_FAILED}),
+			ReleaseMock(&MockReleaseOptions{Name: "twice", Version: 1}),
+			ReleaseMock(&MockReleaseOptions{Name: "twice", Version: 2}),
+		},
+	}
+
+	tests := []struct {
+		name    string
+		release string
+		version int32
+		wantErr bool
+	}{
+		{"healthy", "healthy", 2, false},
+		{"no deployed revision", "undeployed", 0, true},
+		{"unknown release", "missing", 0, true},
+		{"several deployed revisions", "twice", 0, true},
+	}
+
+	for _, tt := range tests {
+		rel, err := c.Deployed(tt.release)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %t, got %v", tt.name, tt.wantErr, err)
+			continue
+		}
+		if err == nil && rel.Version != tt.version {
+			t.Errorf("%s: expected revision %d, got %d", tt.name, tt.version, rel.Version)
+		}
+	}
+}
+
+func TestFakeClient_FailInstallsAfter(t *testing.T) {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "partial", Version: "0.1.0"},
+		Templates: []*chart.Template{
+			{Name: "templates/ns.yaml", Data: []byte("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: partial-ns\n")},
+			{Name: "templates/cm.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: partial-config\n")},
+			{Name: "templates/deploy.yaml", Data: []byte("apiVersion: extensions/v1beta1\nkind: Deployment\nmetadata:\n  name: partial-deploy\n")},
+		},
+	}
+	c := &FakeClient{RenderManifests: true}
+	c.FailInstallsAfter(2)
+
+	resp, err := c.InstallReleaseFromChart(ch, "default", ReleaseName("partial"))
+	if err == nil {
+		t.Fatal("Expected the install to fail")
+	}
+	rel, err := c.GetRevision("partial", 1)
+	if err != nil {
+		t.Fatalf("Expected the failed release to be stored: %s", err)
+	}
+	if resp.Release != rel {
+		t.Error("Expected the failed release to be returned with the error")
+	}
+	if code := rel.Info.Status.Code; code != release.Status_FAILED {
+		t.Errorf("Expected the release to be FAILED, got %s", code)
+	}
+	for _, name := range []string{"partial-ns", "partial-config"} {
+		if !strings.Contains(rel.Manifest, name) {
+			t.Errorf("Expected created resource %s in manifest %q", name, rel.Manifest)
+		}
+	}
+	if strings.Contains(rel.Manifest, "partial-deploy") {
+		t.Errorf("Expected the resource that was not created to be missing from manifest %q", rel.Manifest)
+	}
+
+	c.FailInstallsAfter(3)
+	if _, err := c.InstallReleaseFromChart(ch, "default", ReleaseName("complete")); err != nil {
+		t.Errorf("Expected an install of no more resources than the limit to succeed, got %s", err)
+	}
+	c.FailInstallsAfter(-1)
+	if _, err := c.InstallReleaseFromChart(ch, "default", ReleaseName("restored")); err != nil {
+		t.Errorf("Expected installs to succeed again, got %s", err)
+	}
+}
+
+func TestFakeClient_Clock(t *testing.T) {
+	ch := renderableRelease().Chart
+	c := &FakeClient{RenderManifests: true}
+
+	installed, err := c.InstallReleaseFromChart(ch, "default", ReleaseName("clocked"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := installed.Release.Info.FirstDeployed
+	stamps := []*timestamp.Timestamp{installed.Release.Info.LastDeployed}
+	for i := 0; i < 2; i++ {
+		upgraded, err := c.UpdateReleaseFromChart("clocked", ch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(upgraded.Release.Info.FirstDeployed, first) {
+			t.Errorf("Expected the upgrade to keep the first deployment %v, got %v", first, upgraded.Release.Info.FirstDeployed)
+		}
+		stamps = append(stamps, upgraded.Release.Info.LastDeployed)
+	}
+	rolledBack, err := c.RollbackRelease("clocked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stamps = append(stamps, rolledBack.Release.Info.LastDeployed)
+
+	for i := 1; i < len(stamps); i++ {
+		if stamps[i].Seconds <= stamps[i-1].Seconds {
+			t.Errorf("Expected increasing timestamps, got %v", stamps)
+		}
+	}
+
+	fixed := time.Date(2018, time.June, 1, 0, 0, 0, 0, time.UTC)
+	c = &FakeClient{Now: func() time.Time { return fixed }}
+	installed, err = c.InstallReleaseFromChart(ch, "default", ReleaseName("fixed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := installed.Release.Info.LastDeployed.Seconds; got != fixed.Unix() {
+		t.Errorf("Expected the release to be stamped with %d, got %d", fixed.Unix(), got)
+	}
+}
+
+func TestFakeClient_UpdateReleaseForce(t *testing.T) {
+	tests := []struct {
+		name       string
+		render     bool
+		force      bool
+		wantErr    bool
+		expectCode release.Status_Code
+	}{
+		{"without force", false, false, true, release.Status_FAILED},
+		{"with force", false, true, false, release.Status_DEPLOYED},
+		{"rendered without force", true, false, true, release.Status_FAILED},
+		{"rendered with force", true, true, false, release.Status_DEPLOYED},
+	}
+
+	for _, tt := range tests {
+		rel := renderableRelease()
+		rel.Info.Status.Code = release.Status_FAILED
+		c := &FakeClient{
+			Rels:            []*release.Release{rel},
+			RenderManifests: tt.render,
+			ForceReplaces:   true,
+		}
+
+		resp, err := c.UpdateReleaseFromChart(rel.Name, rel.Chart, UpgradeForce(tt.force))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %t, got %v", tt.name, tt.wantErr, err)
+		}
+		if err == nil && resp.Release.Info.Status.Code != tt.expectCode {
+			t.Errorf("%s: expected the upgraded release to be %s, got %s", tt.name, tt.expectCode, resp.Release.Info.Status.Code)
+		}
+		latest, err := c.GetRevision(rel.Name, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if latest.Info.Status.Code != tt.expectCode {
+			t.Errorf("%s: expected the latest revision to be %s, got %s", tt.name, tt.expectCode, latest.Info.Status.Code)
+		}
+
+		req, ok := c.Calls[0].Request.(*rls.UpdateReleaseRequest)
+		if !ok || req.Force != tt.force {
+			t.Errorf("%s: expected the request to record force=%t, got %v", tt.name, tt.force, c.Calls[0].Request)
+		}
+	}
+}
+
+func TestFakeClient_UpdateReleaseDryRun(t *testing.T) {
+	for _, render := range []bool{false, true} {
+		rel := renderableRelease()
+		orig := proto.Clone(rel).(*release.Release)
+		c := &FakeClient{Rels: []*release.Release{rel}, RenderManifests: render}
+
+		if _, err := c.UpdateReleaseFromChart(rel.Name, rel.Chart, UpgradeDryRun(true)); err != nil {
+			t.Fatal(err)
+		}
+		if len(c.Rels) != 1 || !proto.Equal(c.Rels[0], orig) {
+			t.Errorf("render=%t: expected a dry run to leave the history untouched, got %v", render, c.Rels)
+		}
+
+		// The options of the dry run do not carry over to the next upgrade
+		resp, err := c.UpdateReleaseFromChart(rel.Name, rel.Chart)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if req, ok := c.Calls[1].Request.(*rls.UpdateReleaseRequest); !ok || req.DryRun {
+			t.Errorf("render=%t: expected the next upgrade not to be a dry run, got %v", render, c.Calls[1].Request)
+		}
+		if render && (len(c.Rels) != 2 || resp.Release.Version != 2) {
+			t.Errorf("render=%t: expected the next upgrade to store revision 2, got %v", render, c.Rels)
+		}
+		if !render && proto.Equal(c.Rels[0].Info.LastDeployed, orig.Info.LastDeployed) {
+			t.Errorf("render=%t: expected the next upgrade to update the release", render)
+		}
+	}
+}
+
+func TestFakeClient_AllRevisions(t *testing.T) {
+	c := &FakeClient{
+		Rels: []*release.Release{
+			ReleaseMock(&MockReleaseOptions{Name: "web", Version: 2}),
+			ReleaseMock(&MockReleaseOptions{Name: "db", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+			ReleaseMock(&MockReleaseOptions{Name: "web", Version: 1, StatusCode: release.Status_SUPERSEDED}),
+			ReleaseMock(&MockReleaseOptions{Name: "cache", Version: 1, StatusCode: release.Status_DELETED}),
+			ReleaseMock(&MockReleaseOptions{Name: "db", Version: 2}),
+		},
+	}
+
+	got := []string{}
+	for _, rel := range c.AllRevisions() {
+		got = append(got, fmt.Sprintf("%s.v%d", rel.Name, rel.Version))
+	}
+	expect := []string{"cache.v1", "db.v1", "db.v2", "web.v1", "web.v2"}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected %v, got %v", expect, got)
+	}
+	if c.Rels[0].Name != "web" || c.Rels[0].Version != 2 {
+		t.Error("Expected the stored releases to keep their order")
+	}
+}
+
+func TestFakeClient_RunReleaseTestSummary(t *testing.T) {
+	c := &FakeClient{
+		Responses: map[string]release.TestRun_Status{
+			"PASSED: first":   release.TestRun_SUCCESS,
+			"PASSED: second":  release.TestRun_SUCCESS,
+			"FAILED: third":   release.TestRun_FAILURE,
+			"RUNNING: fourth": release.TestRun_RUNNING,
+			"UNKNOWN: fifth":  release.TestRun_UNKNOWN,
+		},
+	}
+
+	summary, err := c.RunReleaseTestSummary("tested")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Passed != 2 || summary.Failed != 1 || summary.Running != 1 || summary.Unknown != 1 {
+		t.Errorf("Unexpected counts in %+v", summary)
+	}
+	if len(summary.Messages) != len(c.Responses) {
+		t.Errorf("Expected %d messages, got %v", len(c.Responses), summary.Messages)
+	}
+	if summary.Succeeded() {
+		t.Error("Expected a run with a failed test not to succeed")
+	}
+
+	c.Responses = map[string]release.TestRun_Status{"PASSED: only": release.TestRun_SUCCESS}
+	summary, err = c.RunReleaseTestSummary("tested")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !summary.Succeeded() || summary.Passed != 1 {
+		t.Errorf("Expected a passing run, got %+v", summary)
+	}
+}
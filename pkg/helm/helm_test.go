@@ -52,16 +52,19 @@ func TestListReleases_VerifyOptions(t *testing.T) {
 		rls.Status_SUPERSEDED,
 	}
 	var namespace = "namespace"
+	var chartName = "chart"
 
 	// Expected ListReleasesRequest message
 	exp := &tpb.ListReleasesRequest{
-		Limit:       int64(limit),
-		Offset:      offset,
-		Filter:      filter,
-		SortBy:      tpb.ListSort_SortBy(sortBy),
-		SortOrder:   tpb.ListSort_SortOrder(sortOrd),
-		StatusCodes: codes,
-		Namespace:   namespace,
+		Limit:          int64(limit),
+		Offset:         offset,
+		Filter:         filter,
+		SortBy:         tpb.ListSort_SortBy(sortBy),
+		SortOrder:      tpb.ListSort_SortOrder(sortOrd),
+		StatusCodes:    codes,
+		Namespace:      namespace,
+		Chart:          chartName,
+		IncludeDeleted: true,
 	}
 
 	// Options used in ListReleases
@@ -73,6 +76,8 @@ func TestListReleases_VerifyOptions(t *testing.T) {
 		ReleaseListFilter(filter),
 		ReleaseListStatuses(codes),
 		ReleaseListNamespace(namespace),
+		ReleaseListChart(chartName),
+		ReleaseListIncludeDeleted(true),
 	}
 
 	// BeforeCall option to intercept Helm client ListReleasesRequest
@@ -97,6 +102,29 @@ func TestListReleases_VerifyOptions(t *testing.T) {
 	assert(t, "", client.opts.listReq.Filter)
 }
 
+func TestListReleases_AllNamespaces(t *testing.T) {
+	var req *tpb.ListReleasesRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		req, _ = msg.(*tpb.ListReleasesRequest)
+		return errSkip
+	})
+
+	client := NewClient(b4c)
+	if _, err := client.ListReleases(ReleaseListNamespace("other"), ReleaseListAllNamespaces()); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)", err)
+	}
+	if req == nil || req.Namespace != "" {
+		t.Errorf("expected a request for every namespace, got %#+v", req)
+	}
+
+	if _, err := client.ListReleases(ReleaseListAllNamespaces(), ReleaseListNamespace("other")); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)", err)
+	}
+	if req == nil || req.Namespace != "other" {
+		t.Errorf("expected a request for namespace other, got %#+v", req)
+	}
+}
+
 // Verify each InstallOption is applied to an InstallReleaseRequest correctly.
 func TestInstallRelease_VerifyOptions(t *testing.T) {
 	// Options testdata
@@ -118,6 +146,7 @@ func TestInstallRelease_VerifyOptions(t *testing.T) {
 		DisableHooks: disableHooks,
 		Namespace:    namespace,
 		ReuseName:    reuseName,
+		WaitForJobs:  true,
 	}
 
 	// Options used in InstallRelease
@@ -127,6 +156,7 @@ func TestInstallRelease_VerifyOptions(t *testing.T) {
 		ReleaseName(releaseName),
 		InstallReuseName(reuseName),
 		InstallDisableHooks(disableHooks),
+		InstallWaitForJobs(true),
 	}
 
 	// BeforeCall option to intercept Helm client InstallReleaseRequest
@@ -208,6 +238,7 @@ func TestUpdateRelease_VerifyOptions(t *testing.T) {
 		Values:       &cpb.Config{Raw: string(overrides)},
 		DryRun:       dryRun,
 		DisableHooks: disableHooks,
+		WaitForJobs:  true,
 	}
 
 	// Options used in UpdateRelease
@@ -215,6 +246,7 @@ func TestUpdateRelease_VerifyOptions(t *testing.T) {
 		UpgradeDryRun(dryRun),
 		UpdateValueOverrides(overrides),
 		UpgradeDisableHooks(disableHooks),
+		UpgradeWaitForJobs(true),
 	}
 
 	// BeforeCall option to intercept Helm client UpdateReleaseRequest
@@ -248,10 +280,12 @@ func TestRollbackRelease_VerifyOptions(t *testing.T) {
 
 	// Expected RollbackReleaseRequest message
 	exp := &tpb.RollbackReleaseRequest{
-		Name:         releaseName,
-		DryRun:       dryRun,
-		Version:      revision,
-		DisableHooks: disableHooks,
+		Name:          releaseName,
+		DryRun:        dryRun,
+		Version:       revision,
+		DisableHooks:  disableHooks,
+		WaitForJobs:   true,
+		CleanupOnFail: true,
 	}
 
 	// Options used in RollbackRelease
@@ -259,6 +293,8 @@ func TestRollbackRelease_VerifyOptions(t *testing.T) {
 		RollbackDryRun(dryRun),
 		RollbackVersion(revision),
 		RollbackDisableHooks(disableHooks),
+		RollbackWaitForJobs(true),
+		RollbackCleanupOnFail(true),
 	}
 
 	// BeforeCall option to intercept Helm client RollbackReleaseRequest
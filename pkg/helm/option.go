@@ -59,6 +59,8 @@ type options struct {
 	tlsConfig *tls.Config
 	// release list options are applied directly to the list releases request
 	listReq rls.ListReleasesRequest
+	// if set, list releases from every namespace regardless of listReq.Namespace
+	listAllNamespaces bool
 	// release install options are applied directly to the install release request
 	instReq rls.InstallReleaseRequest
 	// release update options are applied directly to the update release request
@@ -159,10 +161,37 @@ func ReleaseListStatuses(statuses []release.Status_Code) ReleaseListOption {
 	}
 }
 
-// ReleaseListNamespace specifies the namespace to list releases from
+// ReleaseListNamespace specifies the namespace to list releases from,
+// overriding ReleaseListAllNamespaces given earlier. The namespace is sent to
+// Tiller as is, and Tiller lists releases from every namespace if it is empty.
 func ReleaseListNamespace(namespace string) ReleaseListOption {
 	return func(opts *options) {
 		opts.listReq.Namespace = namespace
+		opts.listAllNamespaces = false
+	}
+}
+
+// ReleaseListChart only lists releases of the chart with the given name.
+func ReleaseListChart(name string) ReleaseListOption {
+	return func(opts *options) {
+		opts.listReq.Chart = name
+	}
+}
+
+// ReleaseListIncludeDeleted lists DELETED releases along with those matching
+// ReleaseListStatuses, or excludes them unless their status is requested.
+func ReleaseListIncludeDeleted(include bool) ReleaseListOption {
+	return func(opts *options) {
+		opts.listReq.IncludeDeleted = include
+	}
+}
+
+// ReleaseListAllNamespaces lists releases from every namespace, overriding
+// any namespace set earlier with ReleaseListNamespace. ListReleases clears the
+// namespace of the request it sends to Tiller.
+func ReleaseListAllNamespaces() ReleaseListOption {
+	return func(opts *options) {
+		opts.listAllNamespaces = true
 	}
 }
 
@@ -248,6 +277,20 @@ func UpgradeWait(wait bool) UpdateOption {
 	}
 }
 
+// InstallWaitForJobs specifies whether or not to also wait for all Jobs to complete when waiting
+func InstallWaitForJobs(waitForJobs bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.WaitForJobs = waitForJobs
+	}
+}
+
+// UpgradeWaitForJobs specifies whether or not to also wait for all Jobs to complete when waiting
+func UpgradeWaitForJobs(waitForJobs bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.WaitForJobs = waitForJobs
+	}
+}
+
 // RollbackWait specifies whether or not to wait for all resources to be ready
 func RollbackWait(wait bool) RollbackOption {
 	return func(opts *options) {
@@ -255,6 +298,20 @@ func RollbackWait(wait bool) RollbackOption {
 	}
 }
 
+// RollbackWaitForJobs specifies whether or not to also wait for all Jobs to complete when waiting
+func RollbackWaitForJobs(waitForJobs bool) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.WaitForJobs = waitForJobs
+	}
+}
+
+// RollbackCleanupOnFail specifies whether or not to delete the resources created by a failed rollback
+func RollbackCleanupOnFail(cleanupOnFail bool) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.CleanupOnFail = cleanupOnFail
+	}
+}
+
 // UpdateValueOverrides specifies a list of values to include when upgrading
 func UpdateValueOverrides(raw []byte) UpdateOption {
 	return func(opts *options) {
@@ -29,6 +29,12 @@ const HookWeightAnno = "helm.sh/hook-weight"
 // HookDeleteAnno is the label name for the delete policy for a hook
 const HookDeleteAnno = "helm.sh/hook-delete-policy"
 
+// HookOutputLogAnno is the label name for the policy for capturing the logs of a hook
+const HookOutputLogAnno = "helm.sh/hook-output-log-policy"
+
+// HookTimeoutAnno is the label name for the number of seconds a hook may run
+const HookTimeoutAnno = "helm.sh/hook-timeout"
+
 // Types of hooks
 const (
 	PreInstall         = "pre-install"
@@ -42,6 +48,9 @@ const (
 	ReleaseTestSuccess = "test-success"
 	ReleaseTestFailure = "test-failure"
 	CRDInstall         = "crd-install"
+
+	// ReleaseTest is a legacy alias for ReleaseTestSuccess
+	ReleaseTest = "test"
 )
 
 // Type of policy for deleting the hook
@@ -51,6 +60,13 @@ const (
 	BeforeHookCreation = "before-hook-creation"
 )
 
+// Type of policy for capturing the logs of the hook. These are the same values
+// as HookSucceeded and HookFailed.
+const (
+	HookOutputOnSucceeded = "hook-succeeded"
+	HookOutputOnFailed    = "hook-failed"
+)
+
 // FilterTestHooks filters the list of hooks are returns only testing hooks.
 func FilterTestHooks(hooks []*release.Hook) []*release.Hook {
 	testHooks := []*release.Hook{}
@@ -59,10 +75,36 @@ func FilterTestHooks(hooks []*release.Hook) []*release.Hook {
 		for _, e := range h.Events {
 			if e == release.Hook_RELEASE_TEST_SUCCESS || e == release.Hook_RELEASE_TEST_FAILURE {
 				testHooks = append(testHooks, h)
-				continue
+				break
 			}
 		}
 	}
 
 	return testHooks
 }
+
+// FilterHooksByEvent filters the list of hooks and returns only those run on the given event.
+func FilterHooksByEvent(hooks []*release.Hook, event release.Hook_Event) []*release.Hook {
+	filtered := []*release.Hook{}
+
+	for _, h := range hooks {
+		for _, e := range h.Events {
+			if e == event {
+				filtered = append(filtered, h)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// HasDeletePolicy reports whether the hook is to be deleted under the given policy.
+func HasDeletePolicy(h *release.Hook, policy release.Hook_DeletePolicy) bool {
+	for _, p := range h.DeletePolicies {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func testHooks() []*release.Hook {
+	return []*release.Hook{
+		{Name: "install", Events: []release.Hook_Event{release.Hook_PRE_INSTALL}},
+		{Name: "success", Events: []release.Hook_Event{release.Hook_RELEASE_TEST_SUCCESS}},
+		{Name: "failure", Events: []release.Hook_Event{release.Hook_RELEASE_TEST_FAILURE}},
+		{Name: "both", Events: []release.Hook_Event{release.Hook_RELEASE_TEST_SUCCESS, release.Hook_RELEASE_TEST_FAILURE}},
+	}
+}
+
+func names(hooks []*release.Hook) []string {
+	n := []string{}
+	for _, h := range hooks {
+		n = append(n, h.Name)
+	}
+	return n
+}
+
+func TestFilterTestHooks(t *testing.T) {
+	got := names(FilterTestHooks(testHooks()))
+	expect := []string{"success", "failure", "both"}
+	if len(got) != len(expect) {
+		t.Fatalf("Expected %v, got %v", expect, got)
+	}
+	for i := range expect {
+		if got[i] != expect[i] {
+			t.Errorf("Expected %v, got %v", expect, got)
+		}
+	}
+}
+
+func TestFilterHooksByEvent(t *testing.T) {
+	tests := []struct {
+		event  release.Hook_Event
+		expect []string
+	}{
+		{release.Hook_RELEASE_TEST_SUCCESS, []string{"success", "both"}},
+		{release.Hook_RELEASE_TEST_FAILURE, []string{"failure", "both"}},
+		{release.Hook_PRE_INSTALL, []string{"install"}},
+		{release.Hook_POST_DELETE, []string{}},
+	}
+
+	for _, tt := range tests {
+		got := names(FilterHooksByEvent(testHooks(), tt.event))
+		if len(got) != len(tt.expect) {
+			t.Errorf("%s: expected %v, got %v", tt.event, tt.expect, got)
+			continue
+		}
+		for i := range tt.expect {
+			if got[i] != tt.expect[i] {
+				t.Errorf("%s: expected %v, got %v", tt.event, tt.expect, got)
+			}
+		}
+	}
+}
@@ -18,6 +18,7 @@ package kube // import "k8s.io/helm/pkg/kube"
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	goerrors "errors"
 	"fmt"
@@ -39,6 +40,7 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/watch"
@@ -67,6 +69,20 @@ type Client struct {
 	SchemaCacheDir string
 
 	Log func(string, ...interface{})
+	// IngressClassesWithoutStatus lists ingress classes whose controllers never
+	// publish a load balancer address. Ingresses of these classes are not waited on.
+	IngressClassesWithoutStatus []string
+	// ServiceReadiness overrides how Services of a given type are determined to
+	// be ready when waiting. Types without an entry use the default checks.
+	ServiceReadiness map[v1.ServiceType]func(*v1.Service) bool
+	// WaitBackoff controls how often readiness is checked while waiting.
+	// DefaultPollBackoff is used when Initial is not set.
+	WaitBackoff PollBackoff
+	// OwnedPodsOnly restricts the pods waited on for a workload to those it
+	// controls, so that pods of other releases that match its selector are ignored.
+	OwnedPodsOnly bool
+
+	readyCheckers map[schema.GroupKind]ReadyChecker
 }
 
 // New creates a new Client.
@@ -87,6 +103,24 @@ type ResourceActorFunc func(*resource.Info) error
 //
 // Namespace will set the namespace.
 func (c *Client) Create(namespace string, reader io.Reader, timeout int64, shouldWait bool) error {
+	return c.CreateWithOptions(namespace, reader, CreateOptions{
+		Timeout:    timeout,
+		ShouldWait: shouldWait,
+	})
+}
+
+// CreateOptions configures CreateWithOptions.
+type CreateOptions struct {
+	// Timeout is the number of seconds to wait for resources to be ready.
+	Timeout int64
+	// ShouldWait waits for the created resources to be ready.
+	ShouldWait bool
+	// WaitForJobs also waits for Jobs to complete when ShouldWait is set.
+	WaitForJobs bool
+}
+
+// CreateWithOptions is Create with its behavior configured by opts.
+func (c *Client) CreateWithOptions(namespace string, reader io.Reader, opts CreateOptions) error {
 	client, err := c.ClientSet()
 	if err != nil {
 		return err
@@ -103,8 +137,8 @@ func (c *Client) Create(namespace string, reader io.Reader, timeout int64, shoul
 	if err := perform(infos, createResource); err != nil {
 		return err
 	}
-	if shouldWait {
-		return c.waitForResources(time.Duration(timeout)*time.Second, infos)
+	if opts.ShouldWait {
+		return c.waitForResources(context.Background(), time.Duration(opts.Timeout)*time.Second, infos, opts.WaitForJobs)
 	}
 	return nil
 }
@@ -239,15 +273,52 @@ func (c *Client) Get(namespace string, reader io.Reader) (string, error) {
 //
 // Namespace will set the namespaces.
 func (c *Client) Update(namespace string, originalReader, targetReader io.Reader, force bool, recreate bool, timeout int64, shouldWait bool) error {
+	return c.UpdateWithOptions(namespace, originalReader, targetReader, UpdateOptions{
+		Force:      force,
+		Recreate:   recreate,
+		Timeout:    timeout,
+		ShouldWait: shouldWait,
+	})
+}
+
+// UpdateOptions configures UpdateWithOptions.
+type UpdateOptions struct {
+	// Force updates resources through delete/recreate if needed.
+	Force bool
+	// Recreate restarts the pods of updated resources.
+	Recreate bool
+	// Timeout is the number of seconds to wait for resources to be ready.
+	Timeout int64
+	// ShouldWait waits for the updated resources to be ready.
+	ShouldWait bool
+	// WaitForJobs also waits for Jobs to complete when ShouldWait is set.
+	WaitForJobs bool
+	// CleanupOnFail deletes the resources created by the update if it fails.
+	CleanupOnFail bool
+}
+
+// UpdateWithOptions is Update with its behavior configured by opts.
+func (c *Client) UpdateWithOptions(namespace string, originalReader, targetReader io.Reader, opts UpdateOptions) error {
+	created, err := c.update(namespace, originalReader, targetReader, opts)
+	if err != nil && opts.CleanupOnFail {
+		c.cleanup(created)
+	}
+	return err
+}
+
+// update performs Update, returning the resources it created so that they can
+// be cleaned up on failure.
+func (c *Client) update(namespace string, originalReader, targetReader io.Reader, opts UpdateOptions) (Result, error) {
+	var created Result
 	original, err := c.BuildUnstructured(namespace, originalReader)
 	if err != nil {
-		return fmt.Errorf("failed decoding reader into objects: %s", err)
+		return created, fmt.Errorf("failed decoding reader into objects: %s", err)
 	}
 
 	c.Log("building resources from updated manifest")
 	target, err := c.BuildUnstructured(namespace, targetReader)
 	if err != nil {
-		return fmt.Errorf("failed decoding reader into objects: %s", err)
+		return created, fmt.Errorf("failed decoding reader into objects: %s", err)
 	}
 
 	updateErrors := []string{}
@@ -268,6 +339,7 @@ func (c *Client) Update(namespace string, originalReader, targetReader io.Reader
 			if err := createResource(info); err != nil {
 				return fmt.Errorf("failed to create resource: %s", err)
 			}
+			created.Append(info)
 
 			kind := info.Mapping.GroupVersionKind.Kind
 			c.Log("Created a new %s called %q\n", kind, info.Name)
@@ -280,7 +352,7 @@ func (c *Client) Update(namespace string, originalReader, targetReader io.Reader
 			return fmt.Errorf("no %s with the name %q found", kind, info.Name)
 		}
 
-		if err := updateResource(c, info, originalInfo.Object, force, recreate); err != nil {
+		if err := updateResource(c, info, originalInfo.Object, opts.Force, opts.Recreate); err != nil {
 			c.Log("error updating the resource %q:\n\t %v", info.Name, err)
 			updateErrors = append(updateErrors, err.Error())
 		}
@@ -290,9 +362,9 @@ func (c *Client) Update(namespace string, originalReader, targetReader io.Reader
 
 	switch {
 	case err != nil:
-		return err
+		return created, err
 	case len(updateErrors) != 0:
-		return fmt.Errorf(strings.Join(updateErrors, " && "))
+		return created, fmt.Errorf(strings.Join(updateErrors, " && "))
 	}
 
 	for _, info := range original.Difference(target) {
@@ -301,10 +373,21 @@ func (c *Client) Update(namespace string, originalReader, targetReader io.Reader
 			c.Log("Failed to delete %q, err: %s", info.Name, err)
 		}
 	}
-	if shouldWait {
-		return c.waitForResources(time.Duration(timeout)*time.Second, target)
+	if opts.ShouldWait {
+		return created, c.waitForResources(context.Background(), time.Duration(opts.Timeout)*time.Second, target, opts.WaitForJobs)
+	}
+	return created, nil
+}
+
+// cleanup deletes the resources created by a failed update.
+func (c *Client) cleanup(created Result) {
+	c.Log("Cleaning up %d resource(s) created by the failed update", len(created))
+	for _, info := range created {
+		c.Log("Deleting %q in %s...", info.Name, info.Namespace)
+		if err := c.skipIfNotFound(deleteResource(c, info)); err != nil {
+			c.Log("Failed to delete %q, err: %s", info.Name, err)
+		}
 	}
-	return nil
 }
 
 // Delete deletes Kubernetes resources from an io.reader.
@@ -358,6 +441,19 @@ func (c *Client) WatchUntilReady(namespace string, reader io.Reader, timeout int
 	return perform(infos, c.watchTimeout(time.Duration(timeout)*time.Second))
 }
 
+// Wait blocks until all resources in the reader are ready, including the
+// completion of any Jobs.
+//
+// It returns when the resources are ready, the timeout (in seconds) is reached,
+// or ctx is cancelled, in which case ctx.Err() is returned.
+func (c *Client) Wait(ctx context.Context, namespace string, reader io.Reader, timeout int64) error {
+	infos, err := c.BuildUnstructured(namespace, reader)
+	if err != nil {
+		return err
+	}
+	return c.waitForResources(ctx, time.Duration(timeout)*time.Second, infos, true)
+}
+
 func perform(infos Result, fn ResourceActorFunc) error {
 	if len(infos) == 0 {
 		return ErrNoObjectsVisited
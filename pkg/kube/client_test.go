@@ -222,6 +222,49 @@ func TestUpdate(t *testing.T) {
 
 }
 
+func TestUpdateCleanupOnFail(t *testing.T) {
+	listA := newPodList("starfish")
+	listB := newPodList("starfish", "dolphin")
+	listB.Items[0].Spec.Containers[0].Ports = []core.ContainerPort{{Name: "https", ContainerPort: 443}}
+
+	tf := cmdtesting.NewTestFactory()
+	defer tf.Cleanup()
+	tf.UnstructuredClient = &fake.RESTClient{
+		GroupVersion:         schema.GroupVersion{Version: "v1"},
+		NegotiatedSerializer: unstructuredSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			switch {
+			case p == "/namespaces/default/pods/starfish" && m == "GET":
+				return newResponse(200, &listA.Items[0])
+			case p == "/namespaces/default/pods/starfish" && m == "PATCH":
+				return newResponse(500, &metav1.Status{Status: metav1.StatusFailure, Code: 500})
+			case p == "/namespaces/default/pods/dolphin" && m == "GET":
+				return newResponse(404, notFoundBody())
+			case p == "/namespaces/default/pods" && m == "POST":
+				return newResponse(200, &listB.Items[1])
+			default:
+				t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+				return nil, nil
+			}
+		}),
+	}
+
+	c := newTestClient()
+	reaper := &fakeReaper{}
+	rf := &fakeReaperFactory{Factory: tf, reaper: reaper}
+	c.Client.Factory = rf
+	codec := legacyscheme.Codecs.LegacyCodec(scheme.Versions...)
+
+	opts := UpdateOptions{CleanupOnFail: true}
+	if err := c.UpdateWithOptions(core.NamespaceDefault, objBody(codec, &listA), objBody(codec, &listB), opts); err == nil {
+		t.Fatal("expected the update to fail")
+	}
+	if reaper.name != "dolphin" {
+		t.Errorf("expected the created pod to be cleaned up, got %#v", reaper)
+	}
+}
+
 func TestBuild(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -0,0 +1,124 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ReadyChecker determines whether a resource is ready when waiting.
+type ReadyChecker interface {
+	// IsReady reports whether obj, the live state of a resource of kind gvk, is ready.
+	//
+	// Pods, Services and PersistentVolumeClaims are passed as their typed core/v1
+	// objects. Any other kind is passed as an *unstructured.Unstructured.
+	IsReady(gvk schema.GroupVersionKind, obj runtime.Object) (bool, error)
+}
+
+// ReadyCheckerFunc adapts an ordinary function to a ReadyChecker.
+type ReadyCheckerFunc func(gvk schema.GroupVersionKind, obj runtime.Object) (bool, error)
+
+// IsReady calls f(gvk, obj).
+func (f ReadyCheckerFunc) IsReady(gvk schema.GroupVersionKind, obj runtime.Object) (bool, error) {
+	return f(gvk, obj)
+}
+
+var (
+	podGVK     = v1.SchemeGroupVersion.WithKind("Pod")
+	serviceGVK = v1.SchemeGroupVersion.WithKind("Service")
+	pvcGVK     = v1.SchemeGroupVersion.WithKind("PersistentVolumeClaim")
+)
+
+// RegisterReadyChecker makes waits use checker for every resource of the given
+// group and kind, replacing the built-in Pod, Service and PersistentVolumeClaim
+// checks when registered for one of those. It is not safe to call while waiting.
+func (c *Client) RegisterReadyChecker(gk schema.GroupKind, checker ReadyChecker) {
+	if c.readyCheckers == nil {
+		c.readyCheckers = map[schema.GroupKind]ReadyChecker{}
+	}
+	c.readyCheckers[gk] = checker
+}
+
+// readyChecker returns the checker registered for gk, falling back to the
+// built-in ones. It returns nil if there is none.
+func (c *Client) readyChecker(gk schema.GroupKind) ReadyChecker {
+	if checker, ok := c.readyCheckers[gk]; ok {
+		return checker
+	}
+	switch gk {
+	case podGVK.GroupKind():
+		return ReadyCheckerFunc(checkPodReady)
+	case serviceGVK.GroupKind():
+		return ReadyCheckerFunc(c.checkServiceReady)
+	case pvcGVK.GroupKind():
+		return ReadyCheckerFunc(checkVolumeReady)
+	}
+	return nil
+}
+
+// checkReady runs checker on obj, logging and recording obj in status unless it is ready.
+func (c *Client) checkReady(status *waitStatus, checker ReadyChecker, gvk schema.GroupVersionKind, obj runtime.Object) (bool, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false, err
+	}
+	ready, err := checker.IsReady(gvk, obj)
+	if err != nil {
+		return false, err
+	}
+	if !ready {
+		var reason string
+		if pod, ok := obj.(*v1.Pod); ok {
+			reason = podNotReadyReason(pod)
+		}
+		if reason == "" {
+			c.notReady(status, gvk.Kind, accessor, "")
+		} else {
+			c.notReady(status, gvk.Kind, accessor, "%s", reason)
+		}
+	}
+	return ready, nil
+}
+
+func checkPodReady(_ schema.GroupVersionKind, obj runtime.Object) (bool, error) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return false, fmt.Errorf("expected a *v1.Pod, got %T", obj)
+	}
+	return isPodReady(pod), nil
+}
+
+func (c *Client) checkServiceReady(_ schema.GroupVersionKind, obj runtime.Object) (bool, error) {
+	svc, ok := obj.(*v1.Service)
+	if !ok {
+		return false, fmt.Errorf("expected a *v1.Service, got %T", obj)
+	}
+	return c.isServiceReady(svc), nil
+}
+
+func checkVolumeReady(_ schema.GroupVersionKind, obj runtime.Object) (bool, error) {
+	pvc, ok := obj.(*v1.PersistentVolumeClaim)
+	if !ok {
+		return false, fmt.Errorf("expected a *v1.PersistentVolumeClaim, got %T", obj)
+	}
+	return pvc.Status.Phase == v1.ClaimBound, nil
+}
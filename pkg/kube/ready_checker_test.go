@@ -0,0 +1,121 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var cronTabGVK = schema.GroupVersionKind{Group: "stable.example.com", Version: "v1", Kind: "CronTab"}
+
+func newCronTab(name, phase string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "stable.example.com/v1",
+		"kind":       "CronTab",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"status":     map[string]interface{}{"phase": phase},
+	}}
+}
+
+func cronTabReady(gvk schema.GroupVersionKind, obj runtime.Object) (bool, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return false, errors.New("expected an unstructured CronTab")
+	}
+	phase, _, err := unstructured.NestedString(u.Object, "status", "phase")
+	if err != nil {
+		return false, err
+	}
+	return phase == "Running", nil
+}
+
+func TestRegisterReadyChecker(t *testing.T) {
+	c := &Client{Log: nopLogger}
+	if c.readyChecker(cronTabGVK.GroupKind()) != nil {
+		t.Fatal("Expected no checker for an unregistered kind")
+	}
+
+	var calls int
+	c.RegisterReadyChecker(cronTabGVK.GroupKind(), ReadyCheckerFunc(func(gvk schema.GroupVersionKind, obj runtime.Object) (bool, error) {
+		calls++
+		if gvk != cronTabGVK {
+			t.Errorf("Expected %v, got %v", cronTabGVK, gvk)
+		}
+		return cronTabReady(gvk, obj)
+	}))
+	checker := c.readyChecker(cronTabGVK.GroupKind())
+	if checker == nil {
+		t.Fatal("Expected the registered checker")
+	}
+
+	status := &waitStatus{}
+	ready, err := c.checkReady(status, checker, cronTabGVK, newCronTab("running", "Running"))
+	if err != nil || !ready {
+		t.Errorf("Expected running CronTab to be ready, got ready=%t, err=%v", ready, err)
+	}
+	ready, err = c.checkReady(status, checker, cronTabGVK, newCronTab("pending", "Pending"))
+	if err != nil || ready {
+		t.Errorf("Expected pending CronTab to not be ready, got ready=%t, err=%v", ready, err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected checker to be called twice, got %d", calls)
+	}
+	if len(status.notReady) != 1 || status.notReady[0].String() != "CronTab default/pending" {
+		t.Errorf("Expected only the pending CronTab to be reported, got %v", status.notReady)
+	}
+}
+
+func TestRegisterReadyCheckerOverridesBuiltin(t *testing.T) {
+	c := &Client{Log: nopLogger}
+	pods := []v1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Status:     v1.PodStatus{Phase: v1.PodPending},
+	}}
+	if ready, err := c.podsReady(pods, nil, nil); err != nil || ready {
+		t.Fatalf("Expected pending pod to not be ready, got ready=%t, err=%v", ready, err)
+	}
+
+	c.RegisterReadyChecker(podGVK.GroupKind(), ReadyCheckerFunc(func(schema.GroupVersionKind, runtime.Object) (bool, error) {
+		return true, nil
+	}))
+	if ready, err := c.podsReady(pods, nil, nil); err != nil || !ready {
+		t.Errorf("Expected registered checker to mark the pod ready, got ready=%t, err=%v", ready, err)
+	}
+
+	c.RegisterReadyChecker(podGVK.GroupKind(), ReadyCheckerFunc(func(schema.GroupVersionKind, runtime.Object) (bool, error) {
+		return false, errors.New("boom")
+	}))
+	if _, err := c.podsReady(pods, nil, nil); err == nil {
+		t.Error("Expected checker error to be returned")
+	}
+}
+
+func TestBuiltinReadyCheckersRejectUnexpectedTypes(t *testing.T) {
+	c := &Client{Log: nopLogger}
+	for _, gvk := range []schema.GroupVersionKind{podGVK, serviceGVK, pvcGVK} {
+		if _, err := c.readyChecker(gvk.GroupKind()).IsReady(gvk, newCronTab("x", "Running")); err == nil {
+			t.Errorf("%s: expected an error for an unstructured object", gvk.Kind)
+		}
+	}
+}
@@ -17,250 +17,1343 @@ limitations under the License.
 package kube // import "k8s.io/helm/pkg/kube"
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	appsv1beta1 "k8s.io/api/apps/v1beta1"
 	appsv1beta2 "k8s.io/api/apps/v1beta2"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/kubernetes/pkg/apis/core/v1/helper"
 	deploymentutil "k8s.io/kubernetes/pkg/controller/deployment/util"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
 )
 
-// deployment holds associated replicaSets for a deployment
+// crdGroupKind identifies CustomResourceDefinitions regardless of the served API version
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// deployment holds associated replicaSets for a deployment, the
+// HorizontalPodAutoscaler scaling it if there is one, and a lookup of the
+// PodDisruptionBudgets covering its pods that allow no disruptions, which is
+// only made when the deployment is not ready
 type deployment struct {
-	replicaSets *extensions.ReplicaSet
-	deployment  *extensions.Deployment
+	replicaSets  *extensions.ReplicaSet
+	deployment   *extensions.Deployment
+	hpa          *autoscalingv1.HorizontalPodAutoscaler
+	blockingPDBs func() ([]policyv1beta1.PodDisruptionBudget, error)
+}
+
+// getDeployment resolves the new ReplicaSet and the autoscaler of the current
+// state of a deployment. It returns nil if the new ReplicaSet does not exist yet.
+func getDeployment(kcs kubernetes.Interface, hpas *autoscalers, currentDeployment *extensions.Deployment) (*deployment, error) {
+	namespace, name := currentDeployment.Namespace, currentDeployment.Name
+	// Find RS associated with deployment
+	newReplicaSet, err := deploymentutil.GetNewReplicaSet(currentDeployment, kcs.ExtensionsV1beta1())
+	if err != nil {
+		return nil, err
+	}
+	hpa, err := hpas.get(namespace, "Deployment", name)
+	if err != nil {
+		return nil, err
+	}
+	// A Deployment scaled to zero is ready without a ReplicaSet, so it is only missing otherwise
+	if newReplicaSet == nil && desiredReplicas(currentDeployment.Spec.Replicas, hpa) != 0 {
+		return nil, nil
+	}
+	return &deployment{
+		replicaSets: newReplicaSet,
+		deployment:  currentDeployment,
+		hpa:         hpa,
+		blockingPDBs: func() ([]policyv1beta1.PodDisruptionBudget, error) {
+			return getBlockingPDBs(kcs, namespace, currentDeployment.Spec.Template.Labels)
+		},
+	}, nil
+}
+
+// getBlockingPDBs returns the PodDisruptionBudgets selecting pods with the given
+// labels that currently allow no disruptions, and so block evicting any of them.
+// Without the permission to list them, or without the policy API, there are none.
+func getBlockingPDBs(kcs kubernetes.Interface, namespace string, podLabels map[string]string) ([]policyv1beta1.PodDisruptionBudget, error) {
+	list, err := kcs.PolicyV1beta1().PodDisruptionBudgets(namespace).List(metav1.ListOptions{})
+	if errors.IsForbidden(err) || errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var blocking []policyv1beta1.PodDisruptionBudget
+	for _, pdb := range list.Items {
+		if pdb.Status.PodDisruptionsAllowed > 0 {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(podLabels)) {
+			continue
+		}
+		blocking = append(blocking, pdb)
+	}
+	return blocking, nil
+}
+
+// pdbReason describes the PodDisruptionBudgets blocking evictions of the pods of a
+// workload, or returns an empty string if there are none.
+func pdbReason(pdbs []policyv1beta1.PodDisruptionBudget) string {
+	if len(pdbs) == 0 {
+		return ""
+	}
+	names := make([]string, len(pdbs))
+	for i, pdb := range pdbs {
+		names[i] = pdb.Name
+	}
+	return fmt.Sprintf("evictions are blocked by PodDisruptionBudget %s allowing no disruptions", strings.Join(names, ", "))
+}
+
+// autoscalers looks up the HorizontalPodAutoscalers scaling workloads, listing
+// those of each namespace at most once per readiness check.
+type autoscalers struct {
+	kcs         kubernetes.Interface
+	byNamespace map[string][]autoscalingv1.HorizontalPodAutoscaler
+}
+
+func newAutoscalers(kcs kubernetes.Interface) *autoscalers {
+	return &autoscalers{kcs: kcs, byNamespace: map[string][]autoscalingv1.HorizontalPodAutoscaler{}}
+}
+
+// get returns the HorizontalPodAutoscaler targeting the named workload, or nil
+// if it is not autoscaled. Without the permission to list autoscalers, or
+// without the autoscaling API, no workload is considered autoscaled.
+func (a *autoscalers) get(namespace, kind, name string) (*autoscalingv1.HorizontalPodAutoscaler, error) {
+	hpas, ok := a.byNamespace[namespace]
+	if !ok {
+		list, err := a.kcs.AutoscalingV1().HorizontalPodAutoscalers(namespace).List(metav1.ListOptions{})
+		switch {
+		case errors.IsForbidden(err), errors.IsNotFound(err):
+		case err != nil:
+			return nil, err
+		default:
+			hpas = list.Items
+		}
+		a.byNamespace[namespace] = hpas
+	}
+	for i := range hpas {
+		ref := hpas[i].Spec.ScaleTargetRef
+		if ref.Kind == kind && ref.Name == name {
+			return &hpas[i], nil
+		}
+	}
+	return nil, nil
 }
 
-// waitForResources polls to get the current status of all pods, PVCs, and Services
-// until all are ready or a timeout is reached
-func (c *Client) waitForResources(timeout time.Duration, created Result) error {
+// waitForResources polls to get the current status of all pods, PVCs, Services,
+// ReplicaSets, StatefulSets, DaemonSets, CustomResourceDefinitions, Ingresses and,
+// if waitForJobs is set, Jobs until all are ready, the timeout is reached or ctx is done
+func (c *Client) waitForResources(ctx context.Context, timeout time.Duration, created Result, waitForJobs bool) error {
 	c.Log("beginning wait for %d resources with timeout of %v", len(created), timeout)
 
 	kcs, err := c.KubernetesClientSet()
 	if err != nil {
 		return err
 	}
-	return wait.Poll(2*time.Second, timeout, func() (bool, error) {
-		pods := []v1.Pod{}
-		services := []v1.Service{}
-		pvc := []v1.PersistentVolumeClaim{}
-		deployments := []deployment{}
-		for _, v := range created {
-			obj, err := v.Versioned()
-			if err != nil && !runtime.IsNotRegisteredError(err) {
+	return c.waitForResourcesReady(ctx, kcs, timeout, created, waitForJobs)
+}
+
+// waitForResourcesReady polls created until it is ready. On timeout the error
+// names every resource that was not ready during the last check, with the reason
+// it was not ready if one is known.
+func (c *Client) waitForResourcesReady(ctx context.Context, kcs kubernetes.Interface, timeout time.Duration, created Result, waitForJobs bool) error {
+	var last *waitStatus
+	err := pollUntilReady(ctx, timeout, c.pollBackoff(), func() (bool, error) {
+		status := &waitStatus{}
+		ready, err := c.resourcesReady(ctx, kcs, created, status, waitForJobs)
+		last = status
+		return ready, err
+	})
+	if err == wait.ErrWaitTimeout && last != nil && len(last.notReady) > 0 {
+		return last.timeoutError()
+	}
+	return err
+}
+
+// WaitForResources blocks until every object in objs is ready, the timeout
+// elapses or ctx is done. Readiness is decided the same way as when waiting for
+// a release, with Jobs waited on as well: the live state of each object is
+// polled by its namespace and name, so objs may be stale copies.
+//
+// Only objects of the typed kinds that are waited on for a release are
+// supported. Other objects, including unstructured ones, are not waited on.
+func (c *Client) WaitForResources(ctx context.Context, timeout time.Duration, objs []runtime.Object) error {
+	created, err := objectsResult(objs)
+	if err != nil {
+		return err
+	}
+	return c.waitForResources(ctx, timeout, created, true)
+}
+
+// WaitForPodTerminated blocks until the named pod reaches a terminal phase, and
+// returns that phase: PodSucceeded or PodFailed. Unlike WaitAndGetCompletedPodPhase,
+// the pod is looked up by name instead of being built from a manifest. It fails
+// if the pod can not be found, the timeout elapses or ctx is done.
+func (c *Client) WaitForPodTerminated(ctx context.Context, namespace, name string, timeout time.Duration) (v1.PodPhase, error) {
+	kcs, err := c.KubernetesClientSet()
+	if err != nil {
+		return v1.PodUnknown, err
+	}
+	return c.waitForPodTerminated(ctx, kcs, namespace, name, timeout)
+}
+
+func (c *Client) waitForPodTerminated(ctx context.Context, kcs kubernetes.Interface, namespace, name string, timeout time.Duration) (v1.PodPhase, error) {
+	c.Log("waiting for pod %s/%s to terminate with timeout of %v", namespace, name, timeout)
+
+	phase := v1.PodUnknown
+	err := pollUntilReady(ctx, timeout, c.pollBackoff(), func() (bool, error) {
+		pod, err := kcs.CoreV1().Pods(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		phase = pod.Status.Phase
+		return phase == v1.PodSucceeded || phase == v1.PodFailed, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return v1.PodUnknown, fmt.Errorf("%s: pod %s/%s is still %s", err, namespace, name, phase)
+	}
+	if err != nil {
+		return v1.PodUnknown, err
+	}
+	return phase, nil
+}
+
+// objectsResult wraps typed objects in a Result. The infos have no mapping, which
+// resourcesReady takes to mean that their objects are typed already.
+func objectsResult(objs []runtime.Object) (Result, error) {
+	created := make(Result, 0, len(objs))
+	for _, obj := range objs {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, &resource.Info{
+			Name:      accessor.GetName(),
+			Namespace: accessor.GetNamespace(),
+			Object:    obj,
+		})
+	}
+	return created, nil
+}
+
+// versioned returns the object of info in the version of its mapping, or the
+// object as it is if info has no mapping.
+func versioned(info *resource.Info) (runtime.Object, error) {
+	if info.Mapping == nil {
+		return info.Object, nil
+	}
+	return info.Versioned()
+}
+
+// WaitForPods blocks until at least count pods in namespace matching selector
+// are ready, the timeout elapses or ctx is done. Readiness is decided the same
+// way as when waiting for a release, including any ReadyChecker registered for Pods.
+func (c *Client) WaitForPods(ctx context.Context, namespace string, selector map[string]string, count int, timeout time.Duration) error {
+	kcs, err := c.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	return c.waitForPods(ctx, kcs, namespace, selector, count, timeout)
+}
+
+func (c *Client) waitForPods(ctx context.Context, kcs kubernetes.Interface, namespace string, selector map[string]string, count int, timeout time.Duration) error {
+	c.Log("beginning wait for %d ready pods matching %v with timeout of %v", count, selector, timeout)
+
+	checker := c.readyChecker(podGVK.GroupKind())
+	var ready int
+	err := pollUntilReady(ctx, timeout, c.pollBackoff(), func() (bool, error) {
+		pods, err := getPods(ctx, kcs, namespace, selector)
+		if err != nil {
+			return false, err
+		}
+		ready = 0
+		for i := range pods {
+			ok, err := checker.IsReady(podGVK, &pods[i])
+			if err != nil {
 				return false, err
 			}
-			switch value := obj.(type) {
-			case *v1.ReplicationController:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *v1.Pod:
-				pod, err := kcs.CoreV1().Pods(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, *pod)
-			case *appsv1.Deployment:
-				currentDeployment, err := kcs.ExtensionsV1beta1().Deployments(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				// Find RS associated with deployment
-				newReplicaSet, err := deploymentutil.GetNewReplicaSet(currentDeployment, kcs.ExtensionsV1beta1())
-				if err != nil || newReplicaSet == nil {
-					return false, err
-				}
-				newDeployment := deployment{
-					newReplicaSet,
-					currentDeployment,
-				}
-				deployments = append(deployments, newDeployment)
-			case *appsv1beta1.Deployment:
-				currentDeployment, err := kcs.ExtensionsV1beta1().Deployments(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				// Find RS associated with deployment
-				newReplicaSet, err := deploymentutil.GetNewReplicaSet(currentDeployment, kcs.ExtensionsV1beta1())
-				if err != nil || newReplicaSet == nil {
-					return false, err
-				}
-				newDeployment := deployment{
-					newReplicaSet,
-					currentDeployment,
-				}
-				deployments = append(deployments, newDeployment)
-			case *appsv1beta2.Deployment:
-				currentDeployment, err := kcs.ExtensionsV1beta1().Deployments(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				// Find RS associated with deployment
-				newReplicaSet, err := deploymentutil.GetNewReplicaSet(currentDeployment, kcs.ExtensionsV1beta1())
-				if err != nil || newReplicaSet == nil {
-					return false, err
-				}
-				newDeployment := deployment{
-					newReplicaSet,
-					currentDeployment,
-				}
-				deployments = append(deployments, newDeployment)
-			case *extensions.Deployment:
-				currentDeployment, err := kcs.ExtensionsV1beta1().Deployments(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				// Find RS associated with deployment
-				newReplicaSet, err := deploymentutil.GetNewReplicaSet(currentDeployment, kcs.ExtensionsV1beta1())
-				if err != nil || newReplicaSet == nil {
-					return false, err
-				}
-				newDeployment := deployment{
-					newReplicaSet,
-					currentDeployment,
-				}
-				deployments = append(deployments, newDeployment)
-			case *extensions.DaemonSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1.DaemonSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1beta2.DaemonSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1.StatefulSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1beta1.StatefulSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1beta2.StatefulSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *extensions.ReplicaSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1beta2.ReplicaSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *appsv1.ReplicaSet:
-				list, err := getPods(kcs, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case *v1.PersistentVolumeClaim:
-				claim, err := kcs.CoreV1().PersistentVolumeClaims(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if ok {
+				ready++
+			}
+		}
+		return ready >= count, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("%s: %d of %d pod(s) matching %s ready", err, ready, count, labels.Set(selector))
+	}
+	return err
+}
+
+// notReadyResource identifies a resource that was found not ready during a wait,
+// and why if known
+type notReadyResource struct {
+	kind      string
+	namespace string
+	name      string
+	reason    string
+}
+
+func (r notReadyResource) path() string {
+	if r.namespace == "" {
+		return r.name
+	}
+	return r.namespace + "/" + r.name
+}
+
+func (r notReadyResource) String() string {
+	if r.reason == "" {
+		return r.kind + " " + r.path()
+	}
+	return r.kind + " " + r.path() + ": " + r.reason
+}
+
+// waitStatus accumulates every resource found not ready during a single pass
+// over the created resources, so that all of them can be reported at once.
+type waitStatus struct {
+	notReady []notReadyResource
+}
+
+// timeoutError describes all resources that were still not ready when the wait timed out
+func (s *waitStatus) timeoutError() error {
+	names := make([]string, len(s.notReady))
+	for i, r := range s.notReady {
+		names[i] = r.String()
+	}
+	return fmt.Errorf("%s: %d resource(s) not ready: %s", wait.ErrWaitTimeout, len(names), strings.Join(names, ", "))
+}
+
+// PollBackoff controls the interval between readiness checks while waiting. The
+// first check happens after Initial, and the interval is multiplied by Factor
+// after every check that is not ready, up to Max (if set).
+type PollBackoff struct {
+	Initial time.Duration
+	Factor  float64
+	Max     time.Duration
+}
+
+// DefaultPollBackoff is used when a Client does not set WaitBackoff.
+var DefaultPollBackoff = PollBackoff{
+	Initial: 500 * time.Millisecond,
+	Factor:  2,
+	Max:     10 * time.Second,
+}
+
+// next returns the interval to use after interval
+func (b PollBackoff) next(interval time.Duration) time.Duration {
+	next := time.Duration(float64(interval) * b.Factor)
+	// A Factor below 1 keeps the interval fixed
+	if next < interval {
+		next = interval
+	}
+	if b.Max > 0 && next > b.Max {
+		return b.Max
+	}
+	return next
+}
+
+func (c *Client) pollBackoff() PollBackoff {
+	if c.WaitBackoff.Initial <= 0 {
+		return DefaultPollBackoff
+	}
+	return c.WaitBackoff
+}
+
+// pollUntilReady runs condition, backing off between runs, until it reports true,
+// it returns an error, the timeout elapses or ctx is done. When ctx is done its
+// error is returned.
+func pollUntilReady(ctx context.Context, timeout time.Duration, backoff PollBackoff, condition wait.ConditionFunc) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	interval := backoff.Initial
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-waitCtx.Done():
+			timer.Stop()
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return wait.ErrWaitTimeout
+		case <-timer.C:
+		}
+
+		ready, err := condition()
+		if err != nil {
+			return err
+		}
+		if ready {
+			return nil
+		}
+		interval = backoff.next(interval)
+	}
+}
+
+// resourcesReady fetches the current state of the created resources and reports
+// whether all of them are ready. Every resource found not ready is recorded in status.
+func (c *Client) resourcesReady(ctx context.Context, kcs kubernetes.Interface, created Result, status *waitStatus, waitForJobs bool) (bool, error) {
+	pods := []v1.Pod{}
+	services := []v1.Service{}
+	pvc := []v1.PersistentVolumeClaim{}
+	deployments := []deployment{}
+	statefulSets := []appsv1.StatefulSet{}
+	daemonSets := []appsv1.DaemonSet{}
+	jobs := []batchv1.Job{}
+	crds := []*unstructured.Unstructured{}
+	ingresses := []extensions.Ingress{}
+	replicaSets := []appsv1.ReplicaSet{}
+	// minReady holds the minReadySeconds of the workload owning each pod, by namespace/name
+	minReady := map[string]int32{}
+	hpas := newAutoscalers(kcs)
+	customDone := true
+	// Namespaced resources may not be found until the namespaces holding them are active
+	if namespacesDone, err := c.namespacesReady(kcs, created, status); err != nil || !namespacesDone {
+		return false, err
+	}
+	for _, v := range created {
+		// CRDs are read generically so that every served apiextensions version is handled
+		if v.Mapping != nil && v.Mapping.GroupVersionKind.GroupKind() == crdGroupKind {
+			crd, err := getUnstructured(v)
+			if err != nil {
+				return false, err
+			}
+			if !c.skipWait(crd) {
+				crds = append(crds, crd)
+			}
+			continue
+		}
+		obj, err := versioned(v)
+		if err != nil && !runtime.IsNotRegisteredError(err) {
+			return false, err
+		}
+		switch value := obj.(type) {
+		case *v1.ReplicationController:
+			rc, err := kcs.CoreV1().ReplicationControllers(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(rc) {
+				continue
+			}
+			list, err := getPods(ctx, kcs, value.Namespace, value.Spec.Selector)
+			if err != nil {
+				return false, err
+			}
+			pods = appendMinReady(pods, minReady, c.ownedPods(rc, list), rc.Spec.MinReadySeconds)
+		case *v1.Pod:
+			pod, err := kcs.CoreV1().Pods(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(pod) {
+				continue
+			}
+			pods = append(pods, *pod)
+		case *appsv1.Deployment, *appsv1beta1.Deployment, *appsv1beta2.Deployment, *extensions.Deployment:
+			// Every version is read through extensions/v1beta1. The ReplicaSet, autoscaler and
+			// budgets are only resolved for deployments that are waited on.
+			accessor := value.(metav1.Object)
+			current, err := kcs.ExtensionsV1beta1().Deployments(accessor.GetNamespace()).Get(accessor.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(current) {
+				continue
+			}
+			newDeployment, err := getDeployment(kcs, hpas, current)
+			if err != nil {
+				return false, err
+			}
+			if newDeployment == nil {
+				c.notReady(status, "Deployment", current, "new ReplicaSet not yet created")
+				return false, nil
+			}
+			deployments = append(deployments, *newDeployment)
+		case *extensions.DaemonSet:
+			ds, err := kcs.AppsV1().DaemonSets(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(ds) {
+				continue
+			}
+			daemonSets = append(daemonSets, *ds)
+			list, err := getPods(ctx, kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+			if err != nil {
+				return false, err
+			}
+			pods = appendMinReady(pods, minReady, c.ownedPods(ds, list), ds.Spec.MinReadySeconds)
+		case *appsv1.DaemonSet:
+			ds, err := kcs.AppsV1().DaemonSets(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(ds) {
+				continue
+			}
+			daemonSets = append(daemonSets, *ds)
+			list, err := getPods(ctx, kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+			if err != nil {
+				return false, err
+			}
+			pods = appendMinReady(pods, minReady, c.ownedPods(ds, list), ds.Spec.MinReadySeconds)
+		case *appsv1beta2.DaemonSet:
+			ds, err := kcs.AppsV1().DaemonSets(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(ds) {
+				continue
+			}
+			daemonSets = append(daemonSets, *ds)
+			list, err := getPods(ctx, kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+			if err != nil {
+				return false, err
+			}
+			pods = appendMinReady(pods, minReady, c.ownedPods(ds, list), ds.Spec.MinReadySeconds)
+		case *appsv1.StatefulSet:
+			sts, err := kcs.AppsV1().StatefulSets(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(sts) {
+				continue
+			}
+			statefulSets = append(statefulSets, *sts)
+			list, err := getPods(ctx, kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+			if err != nil {
+				return false, err
+			}
+			pods = append(pods, c.ownedPods(sts, list)...)
+		case *appsv1beta1.StatefulSet:
+			sts, err := kcs.AppsV1().StatefulSets(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(sts) {
+				continue
+			}
+			statefulSets = append(statefulSets, *sts)
+			list, err := getPods(ctx, kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+			if err != nil {
+				return false, err
+			}
+			pods = append(pods, c.ownedPods(sts, list)...)
+		case *appsv1beta2.StatefulSet:
+			sts, err := kcs.AppsV1().StatefulSets(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(sts) {
+				continue
+			}
+			statefulSets = append(statefulSets, *sts)
+			list, err := getPods(ctx, kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+			if err != nil {
+				return false, err
+			}
+			pods = append(pods, c.ownedPods(sts, list)...)
+		case *extensions.ReplicaSet:
+			rs, err := kcs.AppsV1().ReplicaSets(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(rs) {
+				continue
+			}
+			replicaSets = append(replicaSets, *rs)
+			list, err := getPods(ctx, kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+			if err != nil {
+				return false, err
+			}
+			pods = appendMinReady(pods, minReady, c.ownedPods(rs, list), rs.Spec.MinReadySeconds)
+		case *appsv1beta2.ReplicaSet:
+			rs, err := kcs.AppsV1().ReplicaSets(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(rs) {
+				continue
+			}
+			replicaSets = append(replicaSets, *rs)
+			list, err := getPods(ctx, kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+			if err != nil {
+				return false, err
+			}
+			pods = appendMinReady(pods, minReady, c.ownedPods(rs, list), rs.Spec.MinReadySeconds)
+		case *appsv1.ReplicaSet:
+			rs, err := kcs.AppsV1().ReplicaSets(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(rs) {
+				continue
+			}
+			replicaSets = append(replicaSets, *rs)
+			list, err := getPods(ctx, kcs, value.Namespace, value.Spec.Selector.MatchLabels)
+			if err != nil {
+				return false, err
+			}
+			pods = appendMinReady(pods, minReady, c.ownedPods(rs, list), rs.Spec.MinReadySeconds)
+		case *batchv1.Job:
+			if !waitForJobs {
+				continue
+			}
+			job, err := kcs.BatchV1().Jobs(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(job) {
+				continue
+			}
+			jobs = append(jobs, *job)
+		case *v1.PersistentVolumeClaim:
+			claim, err := kcs.CoreV1().PersistentVolumeClaims(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(claim) {
+				continue
+			}
+			if claim.Status.Phase == v1.ClaimPending {
+				lazy, err := waitsForFirstConsumer(kcs, claim)
 				if err != nil {
 					return false, err
 				}
-				pvc = append(pvc, *claim)
-			case *v1.Service:
-				svc, err := kcs.CoreV1().Services(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
+				if lazy {
+					// The claim is only bound once a pod consuming it is scheduled, so its readiness is left to that pod
+					c.Log("PersistentVolumeClaim %s/%s waits for its first consumer, skipping", claim.GetNamespace(), claim.GetName())
+					continue
 				}
-				services = append(services, *svc)
 			}
+			pvc = append(pvc, *claim)
+		case *extensions.Ingress:
+			ing, err := kcs.ExtensionsV1beta1().Ingresses(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(ing) {
+				continue
+			}
+			ingresses = append(ingresses, *ing)
+		case *v1.Service:
+			svc, err := kcs.CoreV1().Services(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(svc) {
+				continue
+			}
+			services = append(services, *svc)
+		default:
+			// Kinds without built-in handling are only waited on when a ReadyChecker is registered for them
+			if v.Mapping == nil {
+				continue
+			}
+			gvk := v.Mapping.GroupVersionKind
+			checker, ok := c.readyCheckers[gvk.GroupKind()]
+			if !ok {
+				continue
+			}
+			live, err := getUnstructured(v)
+			if err != nil {
+				return false, err
+			}
+			if c.skipWait(live) {
+				continue
+			}
+			ready, err := c.checkReady(status, checker, gvk, live)
+			if err != nil {
+				return false, err
+			}
+			customDone = customDone && ready
 		}
-		isReady := c.podsReady(pods) && c.servicesReady(services) && c.volumesReady(pvc) && c.deploymentsReady(deployments)
-		return isReady, nil
-	})
+	}
+	pods, podJobs, err := c.withoutCompletedJobPods(kcs, pods, waitForJobs)
+	if err != nil {
+		return false, err
+	}
+	for i := range podJobs {
+		if !containsJob(jobs, &podJobs[i]) {
+			jobs = append(jobs, podJobs[i])
+		}
+	}
+	jobsDone, err := c.jobsReady(jobs, status)
+	if err != nil {
+		return false, err
+	}
+	deploymentsDone, err := c.deploymentsReady(deployments, status)
+	if err != nil {
+		return false, err
+	}
+	podsDone, err := c.podsReady(pods, minReady, status)
+	if err != nil {
+		return false, err
+	}
+	servicesDone, err := c.servicesReady(services, status)
+	if err != nil {
+		return false, err
+	}
+	volumesDone, err := c.volumesReady(pvc, status)
+	if err != nil {
+		return false, err
+	}
+	// Every check runs so that all not ready resources are reported in a single pass
+	checks := []bool{
+		podsDone,
+		servicesDone,
+		volumesDone,
+		customDone,
+		c.statefulSetsReady(statefulSets, status),
+		c.daemonSetsReady(daemonSets, status),
+		c.crdsReady(crds, status),
+		c.ingressesReady(ingresses, status),
+		c.replicaSetsReady(replicaSets, status),
+		deploymentsDone,
+		jobsDone,
+	}
+	for _, ready := range checks {
+		if !ready {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
-func (c *Client) podsReady(pods []v1.Pod) bool {
-	for _, pod := range pods {
-		if !podutil.IsPodReady(&pod) {
-			c.Log("Pod is not ready: %s/%s", pod.GetNamespace(), pod.GetName())
-			return false
+// namespacesReady checks that every created Namespace has reached the Active phase.
+func (c *Client) namespacesReady(kcs kubernetes.Interface, created Result, status *waitStatus) (bool, error) {
+	ready := true
+	for _, v := range created {
+		if v.Mapping != nil && v.Mapping.GroupVersionKind.GroupKind() == crdGroupKind {
+			continue
+		}
+		obj, err := versioned(v)
+		if err != nil && !runtime.IsNotRegisteredError(err) {
+			return false, err
+		}
+		value, ok := obj.(*v1.Namespace)
+		if !ok {
+			continue
+		}
+		ns, err := kcs.CoreV1().Namespaces().Get(value.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if c.skipWait(ns) {
+			continue
+		}
+		if ns.Status.Phase != v1.NamespaceActive {
+			c.notReady(status, "Namespace", ns, "phase is %q", ns.Status.Phase)
+			ready = false
 		}
 	}
-	return true
+	return ready, nil
 }
 
-func (c *Client) servicesReady(svc []v1.Service) bool {
-	for _, s := range svc {
-		// ExternalName Services are external to cluster so helm shouldn't be checking to see if they're 'ready' (i.e. have an IP Set)
-		if s.Spec.Type == v1.ServiceTypeExternalName {
+// podsReady checks every pod with the pod ReadyChecker. A ready pod owned by a
+// workload with minReadySeconds is only ready once its PodReady condition has
+// been true for that long, so that a pod which briefly became ready before
+// crashing does not end the wait.
+func (c *Client) podsReady(pods []v1.Pod, minReady map[string]int32, status *waitStatus) (bool, error) {
+	checker := c.readyChecker(podGVK.GroupKind())
+	now := metav1.Now()
+	ready := true
+	for i := range pods {
+		pod := &pods[i]
+		ok, err := c.checkReady(status, checker, podGVK, pod)
+		if err != nil {
+			return false, err
+		}
+		if seconds := minReady[pod.Namespace+"/"+pod.Name]; ok && seconds > 0 && pod.Status.Phase != v1.PodSucceeded && !podutil.IsPodAvailable(pod, seconds, now) {
+			c.notReady(status, podGVK.Kind, pod, "ready for less than minReadySeconds (%ds)", seconds)
+			ok = false
+		}
+		ready = ready && ok
+	}
+	return ready, nil
+}
+
+// appendMinReady appends list to pods, recording minReadySeconds for each pod of list in minReady.
+func appendMinReady(pods []v1.Pod, minReady map[string]int32, list []v1.Pod, minReadySeconds int32) []v1.Pod {
+	for _, pod := range list {
+		if minReadySeconds > minReady[pod.Namespace+"/"+pod.Name] {
+			minReady[pod.Namespace+"/"+pod.Name] = minReadySeconds
+		}
+	}
+	return append(pods, list...)
+}
+
+// selectedNodeAnnotation is set on claims whose volume is provisioned for the node a consuming pod was scheduled to
+const selectedNodeAnnotation = "volume.kubernetes.io/selected-node"
+
+// waitsForFirstConsumer reports whether claim is bound lazily, that is its storage class
+// uses the WaitForFirstConsumer binding mode
+func waitsForFirstConsumer(client kubernetes.Interface, claim *v1.PersistentVolumeClaim) (bool, error) {
+	if _, ok := claim.Annotations[selectedNodeAnnotation]; ok {
+		return true, nil
+	}
+	className := helper.GetPersistentVolumeClaimClass(claim)
+	if className == "" {
+		return false, nil
+	}
+	class, err := client.StorageV1().StorageClasses().Get(className, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return class.VolumeBindingMode != nil && *class.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer, nil
+}
+
+// withoutCompletedJobPods filters out pods owned by Jobs that have already
+// completed. Such pods may have failed before a retry succeeded and would
+// otherwise never be considered ready.
+//
+// Pods of a Job with restartPolicy OnFailure are restarted in place while the
+// Job retries, so their ready condition flaps. When waiting for jobs, they are
+// filtered out as well, and their Jobs are returned instead, to be checked by
+// jobsReady. Otherwise they are checked like any other pod. The pods of a Job
+// that opted out of waiting with WaitAnnotation are filtered out.
+func (c *Client) withoutCompletedJobPods(kcs kubernetes.Interface, pods []v1.Pod, waitForJobs bool) ([]v1.Pod, []batchv1.Job, error) {
+	owners := map[string]*batchv1.Job{}
+	skipped := map[string]bool{}
+	filtered := make([]v1.Pod, 0, len(pods))
+	jobs := []batchv1.Job{}
+	for _, pod := range pods {
+		owner := metav1.GetControllerOf(&pod)
+		if owner == nil || owner.Kind != "Job" {
+			filtered = append(filtered, pod)
 			continue
 		}
+		key := pod.Namespace + "/" + owner.Name
+		job, ok := owners[key]
+		if !ok {
+			var err error
+			job, err = kcs.BatchV1().Jobs(pod.Namespace).Get(owner.Name, metav1.GetOptions{})
+			switch {
+			case errors.IsNotFound(err):
+				job = nil
+			case err != nil:
+				return nil, nil, err
+			default:
+				skipped[key] = c.skipWait(job)
+			}
+			owners[key] = job
+		}
+		switch {
+		case job == nil:
+			filtered = append(filtered, pod)
+		case isJobComplete(job), skipped[key]:
+			// the pod is done, whatever its own state, or not waited on
+		case pod.Spec.RestartPolicy == v1.RestartPolicyOnFailure && waitForJobs:
+			if !containsJob(jobs, job) {
+				jobs = append(jobs, *job)
+			}
+		default:
+			filtered = append(filtered, pod)
+		}
+	}
+	return filtered, jobs, nil
+}
+
+// containsJob reports whether jobs holds a Job with the namespace and name of job.
+func containsJob(jobs []batchv1.Job, job *batchv1.Job) bool {
+	for _, j := range jobs {
+		if j.Namespace == job.Namespace && j.Name == job.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func isJobComplete(job *batchv1.Job) bool {
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobComplete && cond.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// isPodReady reports whether pod is ready. Pods that ran to completion, such as
+// those of a Job, are ready as well, while failed pods never are. A running pod
+// must also have finished initializing without any failing init containers.
+//
+// Readiness gates are honored through the PodReady condition, which the kubelet
+// only sets once every condition named in Spec.ReadinessGates is true.
+func isPodReady(pod *v1.Pod) bool {
+	switch pod.Status.Phase {
+	case v1.PodSucceeded:
+		return true
+	case v1.PodFailed:
+		return false
+	}
+	if !isPodInitialized(pod) {
+		return false
+	}
+	return podutil.IsPodReady(pod)
+}
+
+// podNotReadyReason describes why pod is not ready, preferring the state of its
+// containers over its phase. It returns an empty string if nothing is known.
+func podNotReadyReason(pod *v1.Pod) string {
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if w := cs.State.Waiting; w != nil && w.Reason != "" && w.Reason != "PodInitializing" {
+			return fmt.Sprintf("init container %s is waiting: %s", cs.Name, w.Reason)
+		}
+		if t := cs.State.Terminated; t != nil && t.ExitCode != 0 {
+			return fmt.Sprintf("init container %s exited with code %d", cs.Name, t.ExitCode)
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if w := cs.State.Waiting; w != nil && w.Reason != "" {
+			return fmt.Sprintf("container %s is waiting: %s", cs.Name, w.Reason)
+		}
+		if !cs.Ready {
+			return fmt.Sprintf("container %s is not ready", cs.Name)
+		}
+	}
+	if pod.Status.Reason != "" {
+		return fmt.Sprintf("phase %s: %s", pod.Status.Phase, pod.Status.Reason)
+	}
+	if pod.Status.Phase != "" {
+		return fmt.Sprintf("phase %s", pod.Status.Phase)
+	}
+	return ""
+}
 
-		// Make sure the service is not explicitly set to "None" before checking the IP
-		if s.Spec.ClusterIP != v1.ClusterIPNone && !helper.IsServiceIPSet(&s) {
-			c.Log("Service is not ready: %s/%s", s.GetNamespace(), s.GetName())
+// isPodInitialized reports whether all init containers of pod completed successfully
+func isPodInitialized(pod *v1.Pod) bool {
+	_, cond := podutil.GetPodCondition(&pod.Status, v1.PodInitialized)
+	if cond == nil || cond.Status != v1.ConditionTrue {
+		return false
+	}
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if w := cs.State.Waiting; w != nil && w.Reason != "" && w.Reason != "PodInitializing" {
+			// e.g. CrashLoopBackOff or ImagePullBackOff while the init container is restarted
 			return false
 		}
-		// This checks if the service has a LoadBalancer and that balancer has an Ingress defined
-		if s.Spec.Type == v1.ServiceTypeLoadBalancer && s.Status.LoadBalancer.Ingress == nil {
-			c.Log("Service is not ready: %s/%s", s.GetNamespace(), s.GetName())
+		if t := cs.State.Terminated; t != nil && t.ExitCode != 0 {
 			return false
 		}
 	}
 	return true
 }
 
-func (c *Client) volumesReady(vols []v1.PersistentVolumeClaim) bool {
-	for _, v := range vols {
-		if v.Status.Phase != v1.ClaimBound {
-			c.Log("PersistentVolumeClaim is not ready: %s/%s", v.GetNamespace(), v.GetName())
-			return false
+func (c *Client) servicesReady(svc []v1.Service, status *waitStatus) (bool, error) {
+	checker := c.readyChecker(serviceGVK.GroupKind())
+	ready := true
+	for i := range svc {
+		ok, err := c.checkReady(status, checker, serviceGVK, &svc[i])
+		if err != nil {
+			return false, err
 		}
+		ready = ready && ok
 	}
-	return true
+	return ready, nil
+}
+
+// isServiceReady reports whether s is ready, consulting ServiceReadiness first
+func (c *Client) isServiceReady(s *v1.Service) bool {
+	if check, ok := c.ServiceReadiness[s.Spec.Type]; ok {
+		return check(s)
+	}
+	return isServiceReady(s)
+}
+
+// isServiceReady is the default readiness check for Services
+func isServiceReady(s *v1.Service) bool {
+	switch {
+	case s.Spec.Type == v1.ServiceTypeExternalName:
+		// ExternalName Services are external to cluster so helm shouldn't be checking to see if they're 'ready' (i.e. have an IP Set)
+		return true
+	case s.Spec.ClusterIP == v1.ClusterIPNone:
+		// Headless Services never get a cluster IP, with or without a selector
+		return true
+	case s.Spec.Type == v1.ServiceTypeNodePort:
+		// Node ports are allocated when the Service is created
+		return true
+	case s.Spec.Type == v1.ServiceTypeLoadBalancer:
+		// This checks if the service has a LoadBalancer and that balancer has an Ingress defined
+		return helper.IsServiceIPSet(s) && len(s.Status.LoadBalancer.Ingress) > 0
+	}
+	return helper.IsServiceIPSet(s)
+}
+
+func (c *Client) volumesReady(vols []v1.PersistentVolumeClaim, status *waitStatus) (bool, error) {
+	checker := c.readyChecker(pvcGVK.GroupKind())
+	ready := true
+	for i := range vols {
+		ok, err := c.checkReady(status, checker, pvcGVK, &vols[i])
+		if err != nil {
+			return false, err
+		}
+		ready = ready && ok
+	}
+	return ready, nil
 }
 
-func (c *Client) deploymentsReady(deployments []deployment) bool {
+// deploymentsReady reports whether all deployments have been fully rolled out. An
+// error is returned as soon as a deployment has exceeded its progress deadline,
+// since it will not make any further progress on its own.
+func (c *Client) deploymentsReady(deployments []deployment, status *waitStatus) (bool, error) {
+	ready := true
 	for _, v := range deployments {
-		if !(v.replicaSets.Status.ReadyReplicas >= *v.deployment.Spec.Replicas-deploymentutil.MaxUnavailable(*v.deployment)) {
-			c.Log("Deployment is not ready: %s/%s", v.deployment.GetNamespace(), v.deployment.GetName())
-			return false
+		reason, err := deploymentNotReadyReason(v)
+		if err != nil {
+			return false, err
+		}
+		if reason == "" {
+			continue
+		}
+		ready = false
+		// A rollout stalled by a PodDisruptionBudget is reported as the likely cause
+		if v.blockingPDBs != nil {
+			pdbs, err := v.blockingPDBs()
+			if err != nil {
+				return false, err
+			}
+			if r := pdbReason(pdbs); r != "" {
+				reason += "; " + r
+			}
+		}
+		c.notReady(status, "Deployment", v.deployment, "%s", reason)
+	}
+	return ready, nil
+}
+
+// deploymentNotReadyReason returns why a deployment has not been fully rolled
+// out, or an empty string if it has.
+func deploymentNotReadyReason(v deployment) (string, error) {
+	d := v.deployment
+	replicas := desiredReplicas(d.Spec.Replicas, v.hpa)
+	// Without desired pods there is nothing to roll out
+	if replicas == 0 {
+		return "", nil
+	}
+	// Until the controller has observed the latest spec the status describes the previous rollout
+	if d.Status.ObservedGeneration < d.Generation {
+		return fmt.Sprintf("generation %d has not been observed yet", d.Generation), nil
+	}
+	if cond := deploymentutil.GetDeploymentCondition(d.Status, extensions.DeploymentProgressing); cond != nil &&
+		cond.Status == v1.ConditionFalse && cond.Reason == deploymentutil.TimedOutReason {
+		return "", fmt.Errorf("deployment %s/%s exceeded its progress deadline: %s", d.GetNamespace(), d.GetName(), cond.Message)
+	}
+
+	// The new ReplicaSet must belong to the revision the Deployment is currently rolling out
+	if v.replicaSets.Annotations[deploymentutil.RevisionAnnotation] != d.Annotations[deploymentutil.RevisionAnnotation] {
+		return fmt.Sprintf("ReplicaSet %s is not the current revision", v.replicaSets.GetName()), nil
+	}
+	if d.Status.UpdatedReplicas != replicas {
+		return fmt.Sprintf("%d out of %d expected pods have been updated", d.Status.UpdatedReplicas, replicas), nil
+	}
+	if d.Status.Replicas != d.Status.UpdatedReplicas {
+		return fmt.Sprintf("%d old pods are pending termination", d.Status.Replicas-d.Status.UpdatedReplicas), nil
+	}
+	if d.Status.AvailableReplicas != d.Status.UpdatedReplicas {
+		return fmt.Sprintf("%d out of %d updated pods are available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas), nil
+	}
+	return "", nil
+}
+
+// desiredReplicas returns the number of replicas a workload should be running.
+// When it is autoscaled the autoscaler decides, rather than the static spec.
+func desiredReplicas(specReplicas *int32, hpa *autoscalingv1.HorizontalPodAutoscaler) int32 {
+	if hpa != nil {
+		if hpa.Status.DesiredReplicas > 0 {
+			return hpa.Status.DesiredReplicas
+		}
+		if hpa.Status.CurrentReplicas > 0 {
+			return hpa.Status.CurrentReplicas
+		}
+	}
+	if specReplicas != nil {
+		return *specReplicas
+	}
+	return 1
+}
+
+func (c *Client) replicaSetsReady(replicaSets []appsv1.ReplicaSet, status *waitStatus) bool {
+	ready := true
+	for _, rs := range replicaSets {
+		if rs.Status.ObservedGeneration < rs.Generation {
+			c.notReady(status, "ReplicaSet", &rs, "generation %d has not been observed yet", rs.Generation)
+			ready = false
+			continue
+		}
+		replicas := int32(1)
+		if rs.Spec.Replicas != nil {
+			replicas = *rs.Spec.Replicas
+		}
+		if rs.Status.ReadyReplicas != replicas {
+			c.notReady(status, "ReplicaSet", &rs, "%d out of %d expected pods are ready", rs.Status.ReadyReplicas, replicas)
+			ready = false
 		}
 	}
+	return ready
+}
+
+func (c *Client) statefulSetsReady(statefulSets []appsv1.StatefulSet, status *waitStatus) bool {
+	ready := true
+	for _, sts := range statefulSets {
+		// OnDelete StatefulSets are never rolled out by the controller, so there is nothing to wait for
+		if sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+			continue
+		}
+
+		replicas := int32(1)
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+		// Without desired pods there is nothing to roll out
+		if replicas == 0 {
+			continue
+		}
+		// With a partitioned rolling update only the ordinals at or above the partition are updated
+		var partition int32
+		if sts.Spec.UpdateStrategy.RollingUpdate != nil && sts.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+			partition = *sts.Spec.UpdateStrategy.RollingUpdate.Partition
+		}
+		expectedUpdated := replicas - partition
+		if expectedUpdated < 0 {
+			expectedUpdated = 0
+		}
+
+		if sts.Status.UpdatedReplicas < expectedUpdated {
+			c.notReady(status, "StatefulSet", &sts, "%d out of %d expected pods have been updated", sts.Status.UpdatedReplicas, expectedUpdated)
+			ready = false
+			continue
+		}
+		if sts.Status.ReadyReplicas != replicas {
+			c.notReady(status, "StatefulSet", &sts, "%d out of %d expected pods are ready", sts.Status.ReadyReplicas, replicas)
+			ready = false
+			continue
+		}
+		if partition == 0 && sts.Status.CurrentRevision != sts.Status.UpdateRevision {
+			c.notReady(status, "StatefulSet", &sts, "update revision %s has not been rolled out", sts.Status.UpdateRevision)
+			ready = false
+		}
+	}
+	return ready
+}
+
+func (c *Client) daemonSetsReady(daemonSets []appsv1.DaemonSet, status *waitStatus) bool {
+	ready := true
+	for _, ds := range daemonSets {
+		desired := ds.Status.DesiredNumberScheduled
+		// Pods of an OnDelete DaemonSet are only replaced manually, so only readiness matters
+		if ds.Spec.UpdateStrategy.Type != appsv1.OnDeleteDaemonSetStrategyType && ds.Status.UpdatedNumberScheduled != desired {
+			c.notReady(status, "DaemonSet", &ds, "%d out of %d expected pods have been updated", ds.Status.UpdatedNumberScheduled, desired)
+			ready = false
+			continue
+		}
+		if ds.Status.NumberReady != desired {
+			c.notReady(status, "DaemonSet", &ds, "%d out of %d expected pods are ready", ds.Status.NumberReady, desired)
+			ready = false
+		}
+	}
+	return ready
+}
+
+// jobsReady reports whether all jobs have completed. An error is returned as soon
+// as any job has failed, since it will never become ready.
+func (c *Client) jobsReady(jobs []batchv1.Job, status *waitStatus) (bool, error) {
+	ready := true
+	for _, job := range jobs {
+		for _, cond := range job.Status.Conditions {
+			if cond.Type == batchv1.JobFailed && cond.Status == v1.ConditionTrue {
+				return false, fmt.Errorf("job failed: %s/%s: %s", job.GetNamespace(), job.GetName(), cond.Reason)
+			}
+		}
+		if job.Spec.BackoffLimit != nil && job.Status.Failed > *job.Spec.BackoffLimit {
+			return false, fmt.Errorf("job failed: %s/%s: %d failed pods exceed the backoff limit of %d", job.GetNamespace(), job.GetName(), job.Status.Failed, *job.Spec.BackoffLimit)
+		}
+
+		// Without completions the job is a work queue: it is done once any pod
+		// has succeeded and the remaining pods have terminated.
+		if job.Spec.Completions == nil {
+			if job.Status.Succeeded < 1 || job.Status.Active > 0 {
+				c.notReady(status, "Job", &job, "%d pods succeeded, %d still active", job.Status.Succeeded, job.Status.Active)
+				ready = false
+			}
+			continue
+		}
+		if job.Status.Succeeded < *job.Spec.Completions {
+			c.notReady(status, "Job", &job, "%d out of %d expected completions", job.Status.Succeeded, *job.Spec.Completions)
+			ready = false
+		}
+	}
+	return ready, nil
+}
+
+// crdsReady reports whether every CustomResourceDefinition has been established
+// by the API server, meaning its custom resources can be served.
+func (c *Client) crdsReady(crds []*unstructured.Unstructured, status *waitStatus) bool {
+	ready := true
+	for _, crd := range crds {
+		if !isCRDEstablished(crd) {
+			c.notReady(status, "CustomResourceDefinition", crd, "")
+			ready = false
+		}
+	}
+	return ready
+}
+
+// ingressClassAnnotation selects the controller responsible for an Ingress
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+// ingressesReady reports whether every Ingress has been assigned an address by its controller
+func (c *Client) ingressesReady(ingresses []extensions.Ingress, status *waitStatus) bool {
+	ready := true
+	for _, ing := range ingresses {
+		if c.skipIngressStatus(&ing) {
+			continue
+		}
+		if len(ing.Status.LoadBalancer.Ingress) == 0 {
+			c.notReady(status, "Ingress", &ing, "no address has been assigned")
+			ready = false
+		}
+	}
+	return ready
+}
+
+func (c *Client) skipIngressStatus(ing *extensions.Ingress) bool {
+	class := ing.Annotations[ingressClassAnnotation]
+	for _, skip := range c.IngressClassesWithoutStatus {
+		if class == skip {
+			return true
+		}
+	}
+	return false
+}
+
+// ownedPods returns the pods of list controlled by owner, or all of them unless
+// OwnedPodsOnly is set.
+func (c *Client) ownedPods(owner metav1.Object, list []v1.Pod) []v1.Pod {
+	if !c.OwnedPodsOnly {
+		return list
+	}
+	owned := make([]v1.Pod, 0, len(list))
+	for _, pod := range list {
+		if ref := metav1.GetControllerOf(&pod); ref != nil && ref.UID == owner.GetUID() {
+			owned = append(owned, pod)
+			continue
+		}
+		c.Log("Pod %s/%s matches the selector of %s but is not controlled by it, skipping", pod.Namespace, pod.Name, owner.GetName())
+	}
+	return owned
+}
+
+// WaitAnnotation opts a resource out of waiting when set to "false" on it.
+const WaitAnnotation = "helm.sh/wait"
+
+// skipWait reports whether the live obj opted out of waiting with WaitAnnotation.
+func (c *Client) skipWait(obj metav1.Object) bool {
+	if obj.GetAnnotations()[WaitAnnotation] != "false" {
+		return false
+	}
+	c.Log("%s/%s has %s set to false, skipping", obj.GetNamespace(), obj.GetName(), WaitAnnotation)
 	return true
 }
 
-func getPods(client kubernetes.Interface, namespace string, selector map[string]string) ([]v1.Pod, error) {
-	list, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{
+// notReady logs that obj is not ready, with an optional reason, and records it
+// in status when one is given.
+func (c *Client) notReady(status *waitStatus, kind string, obj metav1.Object, format string, args ...interface{}) {
+	r := notReadyResource{kind: kind, namespace: obj.GetNamespace(), name: obj.GetName()}
+	if format == "" {
+		c.Log("%s is not ready: %s", kind, r.path())
+	} else {
+		r.reason = fmt.Sprintf(format, args...)
+		c.Log("%s is not ready: %s. %s", kind, r.path(), r.reason)
+	}
+	if status != nil {
+		status.notReady = append(status.notReady, r)
+	}
+}
+
+func isCRDEstablished(crd *unstructured.Unstructured) bool {
+	status, ok := crd.Object["status"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	conditions, ok := status["conditions"].([]interface{})
+	if !ok {
+		return false
+	}
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if cond["type"] == "Established" && cond["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+// getUnstructured fetches the live state of a resource without requiring its type to be registered.
+func getUnstructured(info *resource.Info) (*unstructured.Unstructured, error) {
+	obj, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+	if err != nil {
+		return nil, err
+	}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// podListPageSize bounds the number of pods fetched by a single list request
+const podListPageSize int64 = 500
+
+// getPods lists all pods matching selector, one page at a time
+func getPods(ctx context.Context, client kubernetes.Interface, namespace string, selector map[string]string) ([]v1.Pod, error) {
+	opts := metav1.ListOptions{
 		FieldSelector: fields.Everything().String(),
 		LabelSelector: labels.Set(selector).AsSelector().String(),
-	})
-	return list.Items, err
+		Limit:         podListPageSize,
+	}
+	var pods []v1.Pod
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		list, err := client.CoreV1().Pods(namespace).List(opts)
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, list.Items...)
+		if list.Continue == "" {
+			return pods, nil
+		}
+		opts.Continue = list.Continue
+	}
 }
@@ -0,0 +1,1443 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	testcore "k8s.io/client-go/testing"
+	deploymentutil "k8s.io/kubernetes/pkg/controller/deployment/util"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func newStatefulSet(name string, replicas, updated, ready int32, currentRev, updateRev string) appsv1.StatefulSet {
+	return appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: int32Ptr(replicas),
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			},
+		},
+		Status: appsv1.StatefulSetStatus{
+			Replicas:        replicas,
+			UpdatedReplicas: updated,
+			ReadyReplicas:   ready,
+			CurrentRevision: currentRev,
+			UpdateRevision:  updateRev,
+		},
+	}
+}
+
+func TestStatefulSetsReady(t *testing.T) {
+	c := &Client{Log: nopLogger}
+
+	partitioned := newStatefulSet("partitioned", 3, 1, 3, "rev-1", "rev-2")
+	partitioned.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: int32Ptr(2)}
+
+	onDelete := newStatefulSet("on-delete", 3, 0, 0, "rev-1", "rev-2")
+	onDelete.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+
+	tests := []struct {
+		name   string
+		sts    appsv1.StatefulSet
+		expect bool
+	}{
+		{"fully rolled out", newStatefulSet("ready", 3, 3, 3, "rev-2", "rev-2"), true},
+		{"partially updated", newStatefulSet("rolling", 3, 1, 3, "rev-1", "rev-2"), false},
+		{"updated but not ready", newStatefulSet("starting", 3, 3, 2, "rev-2", "rev-2"), false},
+		{"revision not rolled out", newStatefulSet("pending", 3, 3, 3, "rev-1", "rev-2"), false},
+		{"partitioned rollout", partitioned, true},
+		{"on delete strategy", onDelete, true},
+		{"scaled to zero", newStatefulSet("idle", 0, 0, 0, "rev-1", "rev-2"), true},
+	}
+
+	for _, tt := range tests {
+		if got := c.statefulSetsReady([]appsv1.StatefulSet{tt.sts}, nil); got != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func newDaemonSet(name string, desired, updated, ready int32) appsv1.DaemonSet {
+	return appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: appsv1.DaemonSetSpec{
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+				Type: appsv1.RollingUpdateDaemonSetStrategyType,
+			},
+		},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: desired,
+			CurrentNumberScheduled: desired,
+			UpdatedNumberScheduled: updated,
+			NumberReady:            ready,
+		},
+	}
+}
+
+func TestDaemonSetsReady(t *testing.T) {
+	c := &Client{Log: nopLogger}
+
+	onDelete := newDaemonSet("on-delete", 3, 0, 3)
+	onDelete.Spec.UpdateStrategy.Type = appsv1.OnDeleteDaemonSetStrategyType
+
+	tests := []struct {
+		name   string
+		ds     appsv1.DaemonSet
+		expect bool
+	}{
+		{"fully ready", newDaemonSet("ready", 3, 3, 3), true},
+		{"mid rollout", newDaemonSet("rolling", 3, 1, 3), false},
+		{"still scheduling", newDaemonSet("scheduling", 3, 3, 1), false},
+		{"on delete strategy", onDelete, true},
+	}
+
+	for _, tt := range tests {
+		if got := c.daemonSetsReady([]appsv1.DaemonSet{tt.ds}, nil); got != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func newJob(name string, completions *int32, succeeded, failed, active int32) batchv1.Job {
+	return batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: batchv1.JobSpec{
+			Completions:  completions,
+			Parallelism:  int32Ptr(2),
+			BackoffLimit: int32Ptr(2),
+		},
+		Status: batchv1.JobStatus{
+			Succeeded: succeeded,
+			Failed:    failed,
+			Active:    active,
+		},
+	}
+}
+
+func TestJobsReady(t *testing.T) {
+	c := &Client{Log: nopLogger}
+
+	failedCondition := newJob("failed-condition", int32Ptr(1), 0, 1, 0)
+	failedCondition.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobFailed, Status: v1.ConditionTrue, Reason: "DeadlineExceeded"},
+	}
+
+	tests := []struct {
+		name      string
+		job       batchv1.Job
+		expect    bool
+		expectErr bool
+	}{
+		{"running", newJob("running", int32Ptr(3), 1, 0, 2), false, false},
+		{"succeeded", newJob("succeeded", int32Ptr(3), 3, 0, 0), true, false},
+		{"retrying within backoff limit", newJob("retrying", int32Ptr(1), 0, 2, 1), false, false},
+		{"failed past backoff limit", newJob("failed", int32Ptr(1), 0, 3, 0), false, true},
+		{"failed condition", failedCondition, false, true},
+		{"work queue running", newJob("queue-running", nil, 1, 0, 1), false, false},
+		{"work queue done", newJob("queue-done", nil, 1, 0, 0), true, false},
+	}
+
+	for _, tt := range tests {
+		got, err := c.jobsReady([]batchv1.Job{tt.job}, nil)
+		if (err != nil) != tt.expectErr {
+			t.Errorf("%s: expected error=%t, got %v", tt.name, tt.expectErr, err)
+		}
+		if got != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestResourcesReadyWaitForJobs(t *testing.T) {
+	c := &Client{Log: nopLogger}
+	job := newJob("migrate", int32Ptr(1), 0, 0, 1)
+	kcs := fake.NewSimpleClientset(&job)
+	created := Result{&resource.Info{
+		Name:      job.Name,
+		Namespace: job.Namespace,
+		Object:    &job,
+		Mapping: &meta.RESTMapping{
+			Resource:         "jobs",
+			GroupVersionKind: batchv1.SchemeGroupVersion.WithKind("Job"),
+		},
+	}}
+
+	ready, err := c.resourcesReady(context.Background(), kcs, created, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ready {
+		t.Error("expected running job to be ignored without waitForJobs")
+	}
+
+	ready, err = c.resourcesReady(context.Background(), kcs, created, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ready {
+		t.Error("expected running job to be waited on with waitForJobs")
+	}
+}
+
+func newCRD(apiVersion string, conditions ...map[string]interface{}) *unstructured.Unstructured {
+	conds := []interface{}{}
+	for _, c := range conditions {
+		conds = append(conds, c)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "crontabs.stable.example.com"},
+		"status":     map[string]interface{}{"conditions": conds},
+	}}
+}
+
+func TestCRDsReady(t *testing.T) {
+	c := &Client{Log: nopLogger}
+
+	namesAccepted := map[string]interface{}{"type": "NamesAccepted", "status": "True"}
+	established := map[string]interface{}{"type": "Established", "status": "True"}
+	notEstablished := map[string]interface{}{"type": "Established", "status": "False"}
+
+	tests := []struct {
+		name   string
+		crd    *unstructured.Unstructured
+		expect bool
+	}{
+		{"v1beta1 established", newCRD("apiextensions.k8s.io/v1beta1", namesAccepted, established), true},
+		{"v1 established", newCRD("apiextensions.k8s.io/v1", established), true},
+		{"not established", newCRD("apiextensions.k8s.io/v1beta1", namesAccepted, notEstablished), false},
+		{"no status", newCRD("apiextensions.k8s.io/v1beta1"), false},
+	}
+
+	for _, tt := range tests {
+		if got := c.crdsReady([]*unstructured.Unstructured{tt.crd}, nil); got != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func newDeployment(name string, replicas, total, updated, available int32, revision, rsRevision string) deployment {
+	return deployment{
+		replicaSets: &extensions.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name + "-rs",
+				Namespace:   "default",
+				Annotations: map[string]string{deploymentutil.RevisionAnnotation: rsRevision},
+			},
+		},
+		deployment: &extensions.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{deploymentutil.RevisionAnnotation: revision},
+			},
+			Spec: extensions.DeploymentSpec{Replicas: int32Ptr(replicas)},
+			Status: extensions.DeploymentStatus{
+				Replicas:          total,
+				UpdatedReplicas:   updated,
+				AvailableReplicas: available,
+			},
+		},
+	}
+}
+
+func TestDeploymentsReady(t *testing.T) {
+	c := &Client{Log: nopLogger}
+
+	tests := []struct {
+		name   string
+		dep    deployment
+		expect bool
+	}{
+		{"fully available", newDeployment("ready", 3, 3, 3, 3, "2", "2"), true},
+		{"mid rollout", newDeployment("rolling", 3, 4, 2, 2, "2", "2"), false},
+		{"old pods still running", newDeployment("terminating", 3, 4, 3, 3, "2", "2"), false},
+		{"updated but unavailable", newDeployment("starting", 3, 3, 3, 1, "2", "2"), false},
+		{"stale replica set", newDeployment("stale", 3, 3, 3, 3, "2", "1"), false},
+		{"scaled to zero", newDeployment("idle", 0, 2, 0, 0, "2", "1"), true},
+	}
+
+	for _, tt := range tests {
+		got, err := c.deploymentsReady([]deployment{tt.dep}, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.name, err)
+		}
+		if got != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestPollUntilReadyCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	err := pollUntilReady(ctx, time.Minute, DefaultPollBackoff, func() (bool, error) { return false, nil })
+	if err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected cancellation to return promptly, took %v", elapsed)
+	}
+}
+
+func TestPollUntilReadyDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := pollUntilReady(ctx, time.Minute, DefaultPollBackoff, func() (bool, error) { return false, nil })
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestGetPodsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := getPods(ctx, nil, "default", map[string]string{"app": "foo"}); err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestWaitStatusReportsAllNotReady(t *testing.T) {
+	c := &Client{Log: nopLogger}
+	status := &waitStatus{}
+
+	pods := []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "default"}},
+	}
+	services := []v1.Service{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "lb", Namespace: "other"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, ClusterIP: "10.0.0.1"},
+		},
+	}
+
+	if ready, err := c.podsReady(pods, nil, status); err != nil || ready {
+		t.Errorf("Expected pods to not be ready, got ready=%t, err=%v", ready, err)
+	}
+	if ready, err := c.servicesReady(services, status); err != nil || ready {
+		t.Errorf("Expected services to not be ready, got ready=%t, err=%v", ready, err)
+	}
+	if len(status.notReady) != 3 {
+		t.Fatalf("Expected 3 not ready resources, got %d", len(status.notReady))
+	}
+
+	msg := status.timeoutError().Error()
+	for _, expect := range []string{"Pod default/first", "Pod default/second", "Service other/lb"} {
+		if !strings.Contains(msg, expect) {
+			t.Errorf("Expected %q in error %q", expect, msg)
+		}
+	}
+}
+
+func TestIsPodReady(t *testing.T) {
+	readyCondition := []v1.PodCondition{
+		{Type: v1.PodInitialized, Status: v1.ConditionTrue},
+		{Type: v1.PodReady, Status: v1.ConditionTrue},
+	}
+
+	tests := []struct {
+		name   string
+		status v1.PodStatus
+		expect bool
+	}{
+		{"succeeded", v1.PodStatus{Phase: v1.PodSucceeded}, true},
+		{"failed", v1.PodStatus{Phase: v1.PodFailed}, false},
+		{"failed with stale ready condition", v1.PodStatus{Phase: v1.PodFailed, Conditions: readyCondition}, false},
+		{"running and ready", v1.PodStatus{Phase: v1.PodRunning, Conditions: readyCondition}, true},
+		{"running but not ready", v1.PodStatus{Phase: v1.PodRunning}, false},
+	}
+
+	for _, tt := range tests {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"}, Status: tt.status}
+		if got := isPodReady(pod); got != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestIsPodReadyInitContainers(t *testing.T) {
+	conditions := func(initialized v1.ConditionStatus) []v1.PodCondition {
+		return []v1.PodCondition{
+			{Type: v1.PodInitialized, Status: initialized},
+			{Type: v1.PodReady, Status: v1.ConditionTrue},
+		}
+	}
+	completed := v1.ContainerStatus{
+		Name:  "init",
+		State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"}},
+	}
+	crashing := v1.ContainerStatus{
+		Name:  "init",
+		State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}
+
+	tests := []struct {
+		name   string
+		status v1.PodStatus
+		expect bool
+	}{
+		{
+			name:   "fully initialized",
+			status: v1.PodStatus{Phase: v1.PodRunning, Conditions: conditions(v1.ConditionTrue), InitContainerStatuses: []v1.ContainerStatus{completed}},
+			expect: true,
+		},
+		{
+			name:   "stuck on init container",
+			status: v1.PodStatus{Phase: v1.PodPending, Conditions: conditions(v1.ConditionFalse), InitContainerStatuses: []v1.ContainerStatus{crashing}},
+			expect: false,
+		},
+		{
+			name:   "init container crashing after restart",
+			status: v1.PodStatus{Phase: v1.PodRunning, Conditions: conditions(v1.ConditionTrue), InitContainerStatuses: []v1.ContainerStatus{crashing}},
+			expect: false,
+		},
+		{
+			name:   "missing initialized condition",
+			status: v1.PodStatus{Phase: v1.PodRunning, Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}},
+			expect: false,
+		},
+	}
+
+	for _, tt := range tests {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"}, Status: tt.status}
+		if got := isPodReady(pod); got != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestPodsReadyMinReadySeconds(t *testing.T) {
+	readyFor := func(name string, d time.Duration) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				Conditions: []v1.PodCondition{
+					{Type: v1.PodInitialized, Status: v1.ConditionTrue},
+					{Type: v1.PodReady, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(time.Now().Add(-d))},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		pod      v1.Pod
+		minReady int32
+		expect   bool
+	}{
+		{"just became ready", readyFor("pod", time.Second), 30, false},
+		{"ready long enough", readyFor("pod", time.Minute), 30, true},
+		{"no minReadySeconds", readyFor("pod", 0), 0, true},
+	}
+
+	for _, tt := range tests {
+		c := &Client{Log: nopLogger}
+		status := &waitStatus{}
+		minReady := map[string]int32{"default/pod": tt.minReady}
+		ready, err := c.podsReady([]v1.Pod{tt.pod}, minReady, status)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if ready != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, ready)
+		}
+		if !ready && (len(status.notReady) != 1 || !strings.Contains(status.notReady[0].reason, "minReadySeconds")) {
+			t.Errorf("%s: expected a minReadySeconds reason, got %v", tt.name, status.notReady)
+		}
+	}
+}
+
+func TestAppendMinReady(t *testing.T) {
+	minReady := map[string]int32{}
+	pods := appendMinReady(nil, minReady, []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"}}}, 10)
+	pods = appendMinReady(pods, minReady, []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"}}}, 5)
+	if len(pods) != 2 {
+		t.Errorf("Expected 2 pods, got %d", len(pods))
+	}
+	if minReady["ns/a"] != 10 {
+		t.Errorf("Expected the largest minReadySeconds to be kept, got %d", minReady["ns/a"])
+	}
+}
+
+func newStorageClass(name string, mode storagev1.VolumeBindingMode) *storagev1.StorageClass {
+	return &storagev1.StorageClass{
+		ObjectMeta:        metav1.ObjectMeta{Name: name},
+		Provisioner:       "kubernetes.io/no-provisioner",
+		VolumeBindingMode: &mode,
+	}
+}
+
+func TestWaitsForFirstConsumer(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newStorageClass("immediate", storagev1.VolumeBindingImmediate),
+		newStorageClass("local", storagev1.VolumeBindingWaitForFirstConsumer),
+	)
+	claim := func(class string, annotations map[string]string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", Annotations: annotations},
+			Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: &class},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		claim  *v1.PersistentVolumeClaim
+		expect bool
+	}{
+		{"immediate binding", claim("immediate", nil), false},
+		{"wait for first consumer", claim("local", nil), true},
+		{"selected node", claim("immediate", map[string]string{selectedNodeAnnotation: "node-1"}), true},
+		{"unknown storage class", claim("missing", nil), false},
+	}
+
+	for _, tt := range tests {
+		got, err := waitsForFirstConsumer(client, tt.claim)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.name, err)
+		}
+		if got != tt.expect {
+			t.Errorf("%s: expected %t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestIngressesReady(t *testing.T) {
+	c := &Client{Log: nopLogger, IngressClassesWithoutStatus: []string{"internal"}}
+
+	newIngress := func(name, class string, addresses ...v1.LoadBalancerIngress) extensions.Ingress {
+		return extensions.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{ingressClassAnnotation: class},
+			},
+			Status: extensions.IngressStatus{
+				LoadBalancer: v1.LoadBalancerStatus{Ingress: addresses},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		ing    extensions.Ingress
+		expect bool
+	}{
+		{"assigned address", newIngress("assigned", "nginx", v1.LoadBalancerIngress{IP: "10.0.0.1"}), true},
+		{"no address", newIngress("pending", "nginx"), false},
+		{"class without status", newIngress("internal", "internal"), true},
+	}
+
+	for _, tt := range tests {
+		if got := c.ingressesReady([]extensions.Ingress{tt.ing}, nil); got != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestServicesReady(t *testing.T) {
+	newService := func(svcType v1.ServiceType, clusterIP string, ingress ...v1.LoadBalancerIngress) v1.Service {
+		return v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
+			Spec:       v1.ServiceSpec{Type: svcType, ClusterIP: clusterIP},
+			Status:     v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{Ingress: ingress}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		svc    v1.Service
+		expect bool
+	}{
+		{"cluster ip", newService(v1.ServiceTypeClusterIP, "10.0.0.1"), true},
+		{"cluster ip not assigned", newService(v1.ServiceTypeClusterIP, ""), false},
+		{"node port", newService(v1.ServiceTypeNodePort, ""), true},
+		{"headless", newService(v1.ServiceTypeClusterIP, v1.ClusterIPNone), true},
+		{"load balancer ready", newService(v1.ServiceTypeLoadBalancer, "10.0.0.1", v1.LoadBalancerIngress{IP: "1.2.3.4"}), true},
+		{"load balancer pending", newService(v1.ServiceTypeLoadBalancer, "10.0.0.1"), false},
+		{"external name", newService(v1.ServiceTypeExternalName, ""), true},
+	}
+
+	c := &Client{Log: nopLogger}
+	for _, tt := range tests {
+		got, err := c.servicesReady([]v1.Service{tt.svc}, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.name, err)
+		}
+		if got != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, got)
+		}
+	}
+
+	// A per type override replaces the default check
+	c.ServiceReadiness = map[v1.ServiceType]func(*v1.Service) bool{
+		v1.ServiceTypeLoadBalancer: func(*v1.Service) bool { return true },
+	}
+	if ready, _ := c.servicesReady([]v1.Service{newService(v1.ServiceTypeLoadBalancer, "10.0.0.1")}, nil); !ready {
+		t.Error("Expected override to mark the load balancer ready")
+	}
+}
+
+func TestGetPodsPaginates(t *testing.T) {
+	namedPod := func(name string) v1.Pod {
+		return v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+	pages := []*v1.PodList{
+		{
+			ListMeta: metav1.ListMeta{Continue: "page-2"},
+			Items:    []v1.Pod{namedPod("pod-1"), namedPod("pod-2")},
+		},
+		{
+			ListMeta: metav1.ListMeta{Continue: "page-3"},
+			Items:    []v1.Pod{namedPod("pod-3")},
+		},
+		{
+			Items: []v1.Pod{namedPod("pod-4")},
+		},
+	}
+
+	client := fake.NewSimpleClientset()
+	calls := 0
+	client.PrependReactor("list", "pods", func(action testcore.Action) (bool, runtime.Object, error) {
+		page := pages[calls]
+		calls++
+		return true, page, nil
+	})
+
+	pods, err := getPods(context.Background(), client, "default", map[string]string{"app": "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != len(pages) {
+		t.Errorf("Expected %d list calls, got %d", len(pages), calls)
+	}
+	if len(pods) != 4 {
+		t.Fatalf("Expected 4 pods, got %d", len(pods))
+	}
+	for i, pod := range pods {
+		if expect := fmt.Sprintf("pod-%d", i+1); pod.Name != expect {
+			t.Errorf("Expected pod %s, got %s", expect, pod.Name)
+		}
+	}
+}
+
+func TestPollBackoffGrowsToMax(t *testing.T) {
+	b := PollBackoff{Initial: 500 * time.Millisecond, Factor: 2, Max: 4 * time.Second}
+
+	expected := []time.Duration{
+		500 * time.Millisecond,
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		4 * time.Second,
+	}
+	interval := b.Initial
+	for i, expect := range expected {
+		if interval != expect {
+			t.Errorf("attempt %d: expected interval %v, got %v", i, expect, interval)
+		}
+		interval = b.next(interval)
+	}
+}
+
+func TestPollUntilReadyBacksOff(t *testing.T) {
+	b := PollBackoff{Initial: 10 * time.Millisecond, Factor: 2, Max: 40 * time.Millisecond}
+
+	var calls []time.Time
+	start := time.Now()
+	err := pollUntilReady(context.Background(), time.Minute, b, func() (bool, error) {
+		calls = append(calls, time.Now())
+		return len(calls) == 5, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 5 {
+		t.Fatalf("Expected 5 checks, got %d", len(calls))
+	}
+	// 10ms + 20ms + 40ms + 40ms + 40ms
+	if elapsed := calls[4].Sub(start); elapsed < 150*time.Millisecond {
+		t.Errorf("Expected checks to back off, all 5 ran within %v", elapsed)
+	}
+}
+
+func TestPollUntilReadyTimeout(t *testing.T) {
+	b := PollBackoff{Initial: 10 * time.Millisecond, Factor: 2, Max: 20 * time.Millisecond}
+
+	err := pollUntilReady(context.Background(), 100*time.Millisecond, b, func() (bool, error) { return false, nil })
+	if err != wait.ErrWaitTimeout {
+		t.Errorf("Expected %v, got %v", wait.ErrWaitTimeout, err)
+	}
+}
+
+func TestReplicaSetsReady(t *testing.T) {
+	c := &Client{Log: nopLogger}
+
+	newReplicaSet := func(name string, replicas, ready int32, generation, observed int64) appsv1.ReplicaSet {
+		return appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: generation},
+			Spec:       appsv1.ReplicaSetSpec{Replicas: int32Ptr(replicas)},
+			Status: appsv1.ReplicaSetStatus{
+				Replicas:           ready,
+				ReadyReplicas:      ready,
+				ObservedGeneration: observed,
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		rs     appsv1.ReplicaSet
+		expect bool
+	}{
+		{"fully ready", newReplicaSet("ready", 3, 3, 2, 2), true},
+		{"scaling up", newReplicaSet("scaling", 5, 3, 2, 2), false},
+		{"stale generation", newReplicaSet("stale", 3, 3, 3, 2), false},
+	}
+
+	for _, tt := range tests {
+		if got := c.replicaSetsReady([]appsv1.ReplicaSet{tt.rs}, nil); got != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestDeploymentsReadyObservedGeneration(t *testing.T) {
+	c := &Client{Log: nopLogger}
+
+	// The status still reports the fully available previous rollout
+	dep := newDeployment("upgraded", 3, 3, 3, 3, "2", "2")
+	dep.deployment.Generation = 2
+	dep.deployment.Status.ObservedGeneration = 1
+
+	status := &waitStatus{}
+	ready, err := c.deploymentsReady([]deployment{dep}, status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ready {
+		t.Error("Expected deployment with a stale observedGeneration to not be ready")
+	}
+	if len(status.notReady) != 1 || !strings.Contains(status.notReady[0].reason, "generation 2 has not been observed") {
+		t.Errorf("Expected an unobserved generation reason, got %v", status.notReady)
+	}
+
+	dep.deployment.Status.ObservedGeneration = 2
+	if ready, err = c.deploymentsReady([]deployment{dep}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !ready {
+		t.Error("Expected deployment to be ready once its generation was observed")
+	}
+}
+
+func TestDeploymentsReadyProgressDeadlineExceeded(t *testing.T) {
+	c := &Client{Log: nopLogger}
+
+	dep := newDeployment("stuck", 3, 3, 3, 1, "2", "2")
+	dep.deployment.Status.Conditions = []extensions.DeploymentCondition{
+		{
+			Type:    extensions.DeploymentProgressing,
+			Status:  v1.ConditionFalse,
+			Reason:  deploymentutil.TimedOutReason,
+			Message: `ReplicaSet "stuck-rs" has timed out progressing.`,
+		},
+	}
+
+	ready, err := c.deploymentsReady([]deployment{dep}, nil)
+	if err == nil {
+		t.Fatal("Expected an error for a deployment that exceeded its progress deadline")
+	}
+	if ready {
+		t.Error("Expected deployment to not be ready")
+	}
+	if !strings.Contains(err.Error(), "default/stuck") || !strings.Contains(err.Error(), "timed out progressing") {
+		t.Errorf("Expected error to describe the deployment, got %q", err)
+	}
+}
+
+func newAutoscaler(target string, current, desired int32) *autoscalingv1.HorizontalPodAutoscaler {
+	return &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: target + "-hpa", Namespace: "default"},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{Kind: "Deployment", Name: target},
+			MaxReplicas:    10,
+		},
+		Status: autoscalingv1.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: current,
+			DesiredReplicas: desired,
+		},
+	}
+}
+
+func TestDeploymentsReadyWithAutoscaler(t *testing.T) {
+	c := &Client{Log: nopLogger}
+
+	tests := []struct {
+		name   string
+		dep    deployment
+		expect bool
+	}{
+		// spec asks for 3 but the autoscaler scaled the deployment to 5
+		{"scaled up and available", newDeployment("up", 3, 5, 5, 5, "1", "1"), true},
+		{"scaled up, matching only the spec", newDeployment("up-pending", 3, 3, 3, 3, "1", "1"), false},
+		// spec asks for 3 but the autoscaler scaled the deployment down to 2
+		{"scaled down", newDeployment("down", 3, 2, 2, 2, "1", "1"), true},
+	}
+	tests[0].dep.hpa = newAutoscaler("up", 5, 5)
+	tests[1].dep.hpa = newAutoscaler("up-pending", 3, 5)
+	tests[2].dep.hpa = newAutoscaler("down", 2, 2)
+
+	for _, tt := range tests {
+		got, err := c.deploymentsReady([]deployment{tt.dep}, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.name, err)
+		}
+		if got != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestAutoscalers(t *testing.T) {
+	client := fake.NewSimpleClientset(newAutoscaler("web", 2, 4))
+	hpas := newAutoscalers(client)
+
+	hpa, err := hpas.get("default", "Deployment", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hpa == nil || hpa.Name != "web-hpa" {
+		t.Errorf("Expected autoscaler web-hpa, got %v", hpa)
+	}
+
+	hpa, err = hpas.get("default", "Deployment", "worker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hpa != nil {
+		t.Errorf("Expected no autoscaler, got %s", hpa.Name)
+	}
+
+	if lists := len(client.Actions()); lists != 1 {
+		t.Errorf("Expected the autoscalers of the namespace to be listed once, got %d lists", lists)
+	}
+}
+
+func TestAutoscalersForbidden(t *testing.T) {
+	client := fake.NewSimpleClientset(newAutoscaler("web", 2, 4))
+	client.PrependReactor("list", "horizontalpodautoscalers", func(action testcore.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewForbidden(schema.GroupResource{Group: "autoscaling", Resource: "horizontalpodautoscalers"}, "", fmt.Errorf("not granted"))
+	})
+
+	hpa, err := newAutoscalers(client).get("default", "Deployment", "web")
+	if err != nil {
+		t.Fatalf("Expected a forbidden list to mean no autoscaler, got %s", err)
+	}
+	if hpa != nil {
+		t.Errorf("Expected no autoscaler, got %s", hpa.Name)
+	}
+}
+
+func TestWithoutCompletedJobPods(t *testing.T) {
+	completedJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default", UID: "job-uid"},
+		Status: batchv1.JobStatus{
+			Succeeded:  1,
+			Failed:     1,
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}},
+		},
+	}
+	client := fake.NewSimpleClientset(completedJob)
+
+	isController := true
+	appPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Labels: map[string]string{"app": "foo"}},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	jobPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "migrate-abcde",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "foo"},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "batch/v1", Kind: "Job", Name: "migrate", UID: "job-uid", Controller: &isController},
+			},
+		},
+		Status: v1.PodStatus{Phase: v1.PodFailed},
+	}
+
+	c := &Client{Log: nopLogger}
+	pods, jobs, err := c.withoutCompletedJobPods(client, []v1.Pod{appPod, jobPod}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 1 || pods[0].Name != "app" {
+		t.Errorf("Expected only the app pod, got %v", pods)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("Expected no jobs for a completed job, got %v", jobs)
+	}
+}
+
+func TestWithoutCompletedJobPodsRestartOnFailure(t *testing.T) {
+	retryingJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default", UID: "job-uid"},
+		Spec:       batchv1.JobSpec{BackoffLimit: int32Ptr(6)},
+		Status:     batchv1.JobStatus{Active: 1, Failed: 2},
+	}
+	client := fake.NewSimpleClientset(retryingJob)
+
+	isController := true
+	jobPod := func(name string, policy v1.RestartPolicy) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "batch/v1", Kind: "Job", Name: "migrate", UID: "job-uid", Controller: &isController},
+				},
+			},
+			Spec: v1.PodSpec{RestartPolicy: policy},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{Name: "migrate", RestartCount: 2, State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+				},
+			},
+		}
+	}
+
+	c := &Client{Log: nopLogger}
+	pods, jobs, err := c.withoutCompletedJobPods(client, []v1.Pod{
+		jobPod("migrate-abcde", v1.RestartPolicyOnFailure),
+		jobPod("migrate-fghij", v1.RestartPolicyOnFailure),
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 0 {
+		t.Errorf("Expected the retrying pods to be left to their job, got %v", pods)
+	}
+	if len(jobs) != 1 || jobs[0].Name != "migrate" {
+		t.Fatalf("Expected the owning job once, got %v", jobs)
+	}
+
+	// The job is still retrying within its backoff limit, so it is not ready but has not failed
+	ready, err := c.jobsReady(jobs, &waitStatus{})
+	if err != nil {
+		t.Errorf("Expected a retrying job not to fail the wait, got %s", err)
+	}
+	if ready {
+		t.Error("Expected a retrying job not to be ready")
+	}
+
+	pods, jobs, err = c.withoutCompletedJobPods(client, []v1.Pod{jobPod("migrate-klmno", v1.RestartPolicyNever)}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 1 || len(jobs) != 0 {
+		t.Errorf("Expected a pod that is never restarted to be checked itself, got pods %v and jobs %v", pods, jobs)
+	}
+
+	pods, jobs, err = c.withoutCompletedJobPods(client, []v1.Pod{jobPod("migrate-abcde", v1.RestartPolicyOnFailure)}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 1 || len(jobs) != 0 {
+		t.Errorf("Expected the pod to be checked itself when not waiting for jobs, got pods %v and jobs %v", pods, jobs)
+	}
+
+	retryingJob.Annotations = map[string]string{WaitAnnotation: "false"}
+	client = fake.NewSimpleClientset(retryingJob)
+	pods, jobs, err = c.withoutCompletedJobPods(client, []v1.Pod{jobPod("migrate-abcde", v1.RestartPolicyOnFailure)}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 0 || len(jobs) != 0 {
+		t.Errorf("Expected the pods of a job that is not waited on to be skipped, got pods %v and jobs %v", pods, jobs)
+	}
+}
+
+func TestWaitForPods(t *testing.T) {
+	newPod := func(name string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Status:     v1.PodStatus{Phase: v1.PodPending},
+		}
+	}
+	other := newPod("other")
+	other.Labels = map[string]string{"app": "db"}
+	other.Status = v1.PodStatus{Phase: v1.PodSucceeded}
+	kcs := fake.NewSimpleClientset(newPod("web-1"), newPod("web-2"), other)
+
+	c := &Client{Log: nopLogger, WaitBackoff: PollBackoff{Initial: 5 * time.Millisecond, Factor: 1}}
+	selector := map[string]string{"app": "web"}
+
+	// Pods become ready one after the other while the wait is in progress
+	go func() {
+		for _, name := range []string{"web-1", "web-2"} {
+			time.Sleep(20 * time.Millisecond)
+			pod := newPod(name)
+			pod.Status = v1.PodStatus{
+				Phase: v1.PodRunning,
+				Conditions: []v1.PodCondition{
+					{Type: v1.PodInitialized, Status: v1.ConditionTrue},
+					{Type: v1.PodReady, Status: v1.ConditionTrue},
+				},
+			}
+			if _, err := kcs.CoreV1().Pods("default").Update(pod); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+
+	if err := c.waitForPods(context.Background(), kcs, "default", selector, 2, 5*time.Second); err != nil {
+		t.Fatalf("expected both pods to become ready, got %s", err)
+	}
+
+	err := c.waitForPods(context.Background(), kcs, "default", selector, 3, 50*time.Millisecond)
+	if err == nil {
+		t.Fatal("expected a timeout waiting for more pods than exist")
+	}
+	if !strings.Contains(err.Error(), "2 of 3 pod(s) matching app=web ready") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestWaitForResourcesTimeoutReason(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodInitialized, Status: v1.ConditionTrue},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+			},
+		},
+	}
+	kcs := fake.NewSimpleClientset(pod)
+	created := Result{&resource.Info{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		Object:    pod,
+		Mapping: &meta.RESTMapping{
+			Resource:         "pods",
+			GroupVersionKind: podGVK,
+		},
+	}}
+
+	c := &Client{Log: nopLogger, WaitBackoff: PollBackoff{Initial: 5 * time.Millisecond, Factor: 1}}
+	err := c.waitForResourcesReady(context.Background(), kcs, 50*time.Millisecond, created, false)
+	if err == nil {
+		t.Fatal("expected a timeout waiting for a pod that never becomes ready")
+	}
+	expect := "Pod default/web: container app is waiting: ImagePullBackOff"
+	if !strings.Contains(err.Error(), expect) {
+		t.Errorf("expected %q in error %q", expect, err)
+	}
+}
+
+func TestResourcesReadySkipWaitAnnotation(t *testing.T) {
+	newService := func(name string, annotations map[string]string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, ClusterIP: "10.0.0.1"},
+		}
+	}
+	toInfo := func(svc *v1.Service) *resource.Info {
+		return &resource.Info{
+			Name:      svc.Name,
+			Namespace: svc.Namespace,
+			Object:    svc,
+			Mapping:   &meta.RESTMapping{Resource: "services", GroupVersionKind: serviceGVK},
+		}
+	}
+
+	skipped := newService("skipped", map[string]string{WaitAnnotation: "false"})
+	checked := newService("checked", nil)
+	kcs := fake.NewSimpleClientset(skipped, checked)
+	c := &Client{Log: nopLogger}
+
+	ready, err := c.resourcesReady(context.Background(), kcs, Result{toInfo(skipped)}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ready {
+		t.Error("expected the annotated service to be skipped")
+	}
+
+	status := &waitStatus{}
+	ready, err = c.resourcesReady(context.Background(), kcs, Result{toInfo(skipped), toInfo(checked)}, status, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ready {
+		t.Error("expected the unannotated service to be checked")
+	}
+	if len(status.notReady) != 1 || status.notReady[0].name != "checked" {
+		t.Errorf("expected only the unannotated service to be not ready, got %v", status.notReady)
+	}
+}
+
+func TestResourcesReadySkipWaitDeployment(t *testing.T) {
+	replicas := int32(1)
+	dep := &extensions.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Annotations: map[string]string{WaitAnnotation: "false"}},
+		Spec:       extensions.DeploymentSpec{Replicas: &replicas},
+	}
+	kcs := fake.NewSimpleClientset(dep)
+	c := &Client{Log: nopLogger}
+
+	created := Result{{Name: dep.Name, Namespace: dep.Namespace, Object: dep}}
+	ready, err := c.resourcesReady(context.Background(), kcs, created, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ready {
+		t.Error("expected the annotated deployment to be skipped although it has no ReplicaSet")
+	}
+	// Only the Deployment itself is read; its ReplicaSets and autoscalers are not looked up.
+	if actions := kcs.Actions(); len(actions) != 1 || actions[0].GetResource().Resource != "deployments" {
+		t.Errorf("expected a single deployment get, got %v", actions)
+	}
+}
+
+func TestResourcesReadyDeploymentWithoutReplicaSet(t *testing.T) {
+	replicas := int32(1)
+	dep := &extensions.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       extensions.DeploymentSpec{Replicas: &replicas},
+	}
+	kcs := fake.NewSimpleClientset(dep)
+	c := &Client{Log: nopLogger}
+
+	status := &waitStatus{}
+	created := Result{{Name: dep.Name, Namespace: dep.Namespace, Object: dep}}
+	ready, err := c.resourcesReady(context.Background(), kcs, created, status, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ready {
+		t.Error("expected a deployment without a ReplicaSet not to be ready")
+	}
+	if len(status.notReady) != 1 || status.notReady[0].name != "web" || status.notReady[0].reason != "new ReplicaSet not yet created" {
+		t.Errorf("expected the missing ReplicaSet to be recorded, got %v", status.notReady)
+	}
+}
+
+func TestResourcesReadyOwnedPodsOnly(t *testing.T) {
+	isController := true
+	newPod := func(name, owner string, ready v1.ConditionStatus) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "web"},
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: owner, UID: types.UID(owner + "-uid"), Controller: &isController},
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				Conditions: []v1.PodCondition{
+					{Type: v1.PodInitialized, Status: v1.ConditionTrue},
+					{Type: v1.PodReady, Status: ready},
+				},
+			},
+		}
+	}
+
+	// Both releases label their pods app=web, but only the pods of first belong to it
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "default", UID: "first-uid"},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: int32Ptr(1),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+		Status: appsv1.ReplicaSetStatus{ReadyReplicas: 1},
+	}
+	kcs := fake.NewSimpleClientset(rs, newPod("first-abcde", "first", v1.ConditionTrue), newPod("second-abcde", "second", v1.ConditionFalse))
+	created := Result{&resource.Info{
+		Name:      rs.Name,
+		Namespace: rs.Namespace,
+		Object:    rs,
+		Mapping: &meta.RESTMapping{
+			Resource:         "replicasets",
+			GroupVersionKind: appsv1.SchemeGroupVersion.WithKind("ReplicaSet"),
+		},
+	}}
+
+	c := &Client{Log: nopLogger}
+	status := &waitStatus{}
+	ready, err := c.resourcesReady(context.Background(), kcs, created, status, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ready {
+		t.Error("expected the pod of the other release to be waited on without OwnedPodsOnly")
+	}
+	if len(status.notReady) != 1 || status.notReady[0].name != "second-abcde" {
+		t.Errorf("expected only the pod of the other release to be not ready, got %v", status.notReady)
+	}
+
+	c.OwnedPodsOnly = true
+	ready, err = c.resourcesReady(context.Background(), kcs, created, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ready {
+		t.Error("expected only the pods controlled by the replica set to be waited on")
+	}
+}
+
+func TestWaitForResourcesObjects(t *testing.T) {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, ClusterIP: "10.0.0.1"},
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Status:     v1.PodStatus{Phase: v1.PodPending},
+	}
+	kcs := fake.NewSimpleClientset(svc, pod)
+	c := &Client{Log: nopLogger, WaitBackoff: PollBackoff{Initial: 5 * time.Millisecond, Factor: 1}}
+
+	created, err := objectsResult([]runtime.Object{svc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.waitForResourcesReady(context.Background(), kcs, 50*time.Millisecond, created, true); err != nil {
+		t.Errorf("expected the service to be ready, got %s", err)
+	}
+
+	created, err = objectsResult([]runtime.Object{svc, pod})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.waitForResourcesReady(context.Background(), kcs, 50*time.Millisecond, created, true)
+	if err == nil {
+		t.Fatal("expected a timeout waiting for a pending pod")
+	}
+	if !strings.Contains(err.Error(), "Pod default/web") || strings.Contains(err.Error(), "Service") {
+		t.Errorf("expected only the pod to be reported, got %q", err)
+	}
+}
+
+func TestResourcesReadyNamespaces(t *testing.T) {
+	c := &Client{Log: nopLogger}
+
+	tests := []struct {
+		name   string
+		phase  v1.NamespacePhase
+		expect bool
+	}{
+		{"terminating", v1.NamespaceTerminating, false},
+		{"active", v1.NamespaceActive, true},
+	}
+
+	for _, tt := range tests {
+		ns := &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "team"},
+			Status:     v1.NamespaceStatus{Phase: tt.phase},
+		}
+		svc := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, ClusterIP: "10.0.0.1"},
+		}
+		created, err := objectsResult([]runtime.Object{ns, svc})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		status := &waitStatus{}
+		ready, err := c.resourcesReady(context.Background(), fake.NewSimpleClientset(ns, svc), created, status, true)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if ready != tt.expect {
+			t.Errorf("%s: expected ready=%t, got %t", tt.name, tt.expect, ready)
+		}
+		if !tt.expect && (len(status.notReady) != 1 || status.notReady[0].kind != "Namespace") {
+			t.Errorf("%s: expected only the namespace to be reported, got %v", tt.name, status.notReady)
+		}
+	}
+}
+
+func TestWaitForPodTerminated(t *testing.T) {
+	c := &Client{Log: nopLogger, WaitBackoff: PollBackoff{Initial: 5 * time.Millisecond, Factor: 1}}
+
+	tests := []struct {
+		name    string
+		phase   v1.PodPhase
+		expect  v1.PodPhase
+		wantErr bool
+	}{
+		{"succeeded", v1.PodSucceeded, v1.PodSucceeded, false},
+		{"failed", v1.PodFailed, v1.PodFailed, false},
+		{"still running", v1.PodRunning, v1.PodUnknown, true},
+	}
+
+	for _, tt := range tests {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-hook", Namespace: "default"},
+			Status:     v1.PodStatus{Phase: tt.phase},
+		}
+		phase, err := c.waitForPodTerminated(context.Background(), fake.NewSimpleClientset(pod), "default", "test-hook", 50*time.Millisecond)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %t, got %v", tt.name, tt.wantErr, err)
+		}
+		if phase != tt.expect {
+			t.Errorf("%s: expected phase %s, got %s", tt.name, tt.expect, phase)
+		}
+	}
+
+	if _, err := c.waitForPodTerminated(context.Background(), fake.NewSimpleClientset(), "default", "missing", time.Second); err == nil {
+		t.Error("Expected an error for a missing pod")
+	}
+}
+
+func TestResourcesReadyScaledToZeroDeployment(t *testing.T) {
+	c := &Client{Log: nopLogger}
+	dep := &extensions.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "idle", Namespace: "default", Generation: 1},
+		Spec: extensions.DeploymentSpec{
+			Replicas: int32Ptr(0),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "idle"}},
+			Template: v1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "idle"}}},
+		},
+	}
+	created, err := objectsResult([]runtime.Object{dep})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No ReplicaSet or pods exist for the deployment
+	ready, err := c.resourcesReady(context.Background(), fake.NewSimpleClientset(dep), created, &waitStatus{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ready {
+		t.Error("Expected a deployment scaled to zero to be ready")
+	}
+}
+
+func TestDeploymentsReadyBlockedByPDB(t *testing.T) {
+	newPDB := func(name string, allowed int32, selector map[string]string) *policyv1beta1.PodDisruptionBudget {
+		return &policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       policyv1beta1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: selector}},
+			Status:     policyv1beta1.PodDisruptionBudgetStatus{PodDisruptionsAllowed: allowed},
+		}
+	}
+	kcs := fake.NewSimpleClientset(
+		newPDB("web-strict", 0, map[string]string{"app": "web"}),
+		newPDB("web-lenient", 1, map[string]string{"app": "web"}),
+		newPDB("db-strict", 0, map[string]string{"app": "db"}),
+	)
+
+	pdbs, err := getBlockingPDBs(kcs, "default", map[string]string{"app": "web", "tier": "frontend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pdbs) != 1 || pdbs[0].Name != "web-strict" {
+		t.Fatalf("expected only the strict budget of the web pods to block, got %v", pdbs)
+	}
+
+	lookups := 0
+	blocking := func() ([]policyv1beta1.PodDisruptionBudget, error) {
+		lookups++
+		return pdbs, nil
+	}
+
+	// The rollout is stuck with an old pod that can not be evicted
+	dep := newDeployment("web", 3, 4, 3, 3, "2", "2")
+	dep.blockingPDBs = blocking
+	c := &Client{Log: nopLogger}
+	status := &waitStatus{}
+	ready, err := c.deploymentsReady([]deployment{dep}, status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ready {
+		t.Error("expected the blocked deployment not to be ready")
+	}
+	if len(status.notReady) != 1 || !strings.Contains(status.notReady[0].reason, "PodDisruptionBudget web-strict") {
+		t.Errorf("expected the blocking budget in the reason, got %v", status.notReady)
+	}
+
+	lookups = 0
+	done := newDeployment("web", 3, 3, 3, 3, "2", "2")
+	done.blockingPDBs = blocking
+	if ready, err := c.deploymentsReady([]deployment{done}, nil); err != nil || !ready {
+		t.Errorf("expected the rolled out deployment to be ready, got %t, %v", ready, err)
+	}
+	if lookups != 0 {
+		t.Errorf("expected no budget lookup for a ready deployment, got %d", lookups)
+	}
+}
+
+func TestGetBlockingPDBsForbidden(t *testing.T) {
+	kcs := fake.NewSimpleClientset()
+	kcs.PrependReactor("list", "poddisruptionbudgets", func(action testcore.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewForbidden(schema.GroupResource{Group: "policy", Resource: "poddisruptionbudgets"}, "", fmt.Errorf("not granted"))
+	})
+
+	pdbs, err := getBlockingPDBs(kcs, "default", map[string]string{"app": "web"})
+	if err != nil {
+		t.Fatalf("expected a forbidden list to mean no budgets, got %s", err)
+	}
+	if len(pdbs) != 0 {
+		t.Errorf("expected no budgets, got %v", pdbs)
+	}
+}
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package tiller
+package manifest
 
 import (
 	"sort"
@@ -22,6 +22,22 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/release"
 )
 
+// HookPlan groups hooks by the events they run on, in the order they are
+// executed for each event: by weight, then by name. A hook registered for
+// several events appears in each of their groups.
+func HookPlan(hooks []*release.Hook) map[release.Hook_Event][]*release.Hook {
+	plan := map[release.Hook_Event][]*release.Hook{}
+	for _, h := range hooks {
+		for _, e := range h.Events {
+			plan[e] = append(plan[e], h)
+		}
+	}
+	for e, hs := range plan {
+		plan[e] = sortByHookWeight(hs)
+	}
+	return plan
+}
+
 // sortByHookWeight does an in-place sort of hooks by their supplied weight.
 func sortByHookWeight(hooks []*release.Hook) []*release.Hook {
 	hs := newHookWeightSorter(hooks)
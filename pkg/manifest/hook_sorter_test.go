@@ -14,9 +14,10 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package tiller
+package manifest
 
 import (
+	"strings"
 	"testing"
 
 	"k8s.io/helm/pkg/proto/hapi/release"
@@ -71,3 +72,33 @@ func TestHookSorter(t *testing.T) {
 		t.Errorf("Expected %q, got %q", expect, got)
 	}
 }
+
+func TestHookPlan(t *testing.T) {
+	hooks := []*release.Hook{
+		{Name: "migrate", Weight: 5, Events: []release.Hook_Event{release.Hook_PRE_INSTALL, release.Hook_PRE_UPGRADE}},
+		{Name: "secret", Weight: -5, Events: []release.Hook_Event{release.Hook_PRE_INSTALL}},
+		{Name: "notify", Weight: 0, Events: []release.Hook_Event{release.Hook_POST_INSTALL}},
+		{Name: "backup", Weight: 0, Events: []release.Hook_Event{release.Hook_PRE_UPGRADE}},
+		{Name: "cleanup", Weight: -1, Events: []release.Hook_Event{release.Hook_POST_INSTALL}},
+	}
+
+	expect := map[release.Hook_Event]string{
+		release.Hook_PRE_INSTALL:  "secret,migrate",
+		release.Hook_POST_INSTALL: "cleanup,notify",
+		release.Hook_PRE_UPGRADE:  "backup,migrate",
+	}
+
+	plan := HookPlan(hooks)
+	if len(plan) != len(expect) {
+		t.Errorf("Expected %d events, got %d", len(expect), len(plan))
+	}
+	for event, names := range expect {
+		var got []string
+		for _, h := range plan[event] {
+			got = append(got, h.Name)
+		}
+		if strings.Join(got, ",") != names {
+			t.Errorf("Expected %s hooks %q, got %q", event, names, strings.Join(got, ","))
+		}
+	}
+}
@@ -14,9 +14,10 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package tiller
+package manifest
 
 import (
+	"math"
 	"sort"
 )
 
@@ -87,6 +88,21 @@ var UninstallOrder SortOrder = []string{
 	"Namespace",
 }
 
+// UnknownKindOrdinal is the ordinal of kinds that are not part of a SortOrder.
+// Manifests of unknown kinds are sorted after all known kinds.
+const UnknownKindOrdinal = math.MaxInt32
+
+// KindOrdinal returns the position of kind in order, or UnknownKindOrdinal if
+// order does not contain it.
+func KindOrdinal(kind string, order SortOrder) int {
+	for i, k := range order {
+		if k == kind {
+			return i
+		}
+	}
+	return UnknownKindOrdinal
+}
+
 // sortByKind does an in-place sort of manifests by Kind.
 //
 // Results are sorted by 'ordering'
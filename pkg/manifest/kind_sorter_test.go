@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package tiller
+package manifest
 
 import (
 	"bytes"
@@ -219,3 +219,26 @@ func TestKindSorterSubSort(t *testing.T) {
 		})
 	}
 }
+
+func TestKindOrdinal(t *testing.T) {
+	tests := []struct {
+		kind      string
+		install   int
+		uninstall int
+	}{
+		{"Namespace", 0, len(UninstallOrder) - 1},
+		{"Secret", 4, len(UninstallOrder) - 5},
+		{"Deployment", 20, 6},
+		{"APIService", len(InstallOrder) - 1, 0},
+		{"HonkyTonkSet", UnknownKindOrdinal, UnknownKindOrdinal},
+	}
+
+	for _, tt := range tests {
+		if got := KindOrdinal(tt.kind, InstallOrder); got != tt.install {
+			t.Errorf("%s: expected install ordinal %d, got %d", tt.kind, tt.install, got)
+		}
+		if got := KindOrdinal(tt.kind, UninstallOrder); got != tt.uninstall {
+			t.Errorf("%s: expected uninstall ordinal %d, got %d", tt.kind, tt.uninstall, got)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	util "k8s.io/helm/pkg/releaseutil"
+)
+
+// ManifestLintError indicates that a manifest parsed, but is not a valid resource.
+const ManifestLintError ManifestErrorReason = "lint"
+
+// Lint checks the same rendered files as Partition, and returns an error for
+// every resource that lacks a kind or a metadata.name, which Partition accepts
+// but can not be applied. Errors are ordered by file path, and then by the
+// position of the resource in its file. Documents that do not parse are
+// reported with ManifestParseError.
+func Lint(files map[string]string) []*ManifestError {
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	var errs []*ManifestError
+	for _, filePath := range paths {
+		c := files[filePath]
+		if strings.HasPrefix(path.Base(filePath), "_") || strings.HasSuffix(filePath, notesFile) || len(strings.TrimSpace(c)) == 0 {
+			continue
+		}
+		entries := util.SplitManifests(c)
+		for i := 0; i < len(entries); i++ {
+			var head util.SimpleHead
+			if err := yaml.Unmarshal([]byte(entries[fmt.Sprintf("manifest-%d", i)]), &head); err != nil {
+				errs = append(errs, &ManifestError{Path: filePath, Reason: ManifestParseError, Err: err})
+				continue
+			}
+			// Documents holding nothing but comments are not resources
+			if head.Version == "" && head.Kind == "" && head.Metadata == nil {
+				continue
+			}
+			if head.Kind == "" {
+				errs = append(errs, &ManifestError{Path: filePath, Reason: ManifestLintError, Err: errors.New("resource has no kind")})
+			}
+			if head.Metadata == nil || head.Metadata.Name == "" {
+				errs = append(errs, &ManifestError{Path: filePath, Reason: ManifestLintError, Err: fmt.Errorf("%s has no metadata.name", resourceKind(head.Kind))})
+			}
+		}
+	}
+	return errs
+}
+
+// resourceKind describes a resource of kind in an error message.
+func resourceKind(kind string) string {
+	if kind == "" {
+		return "resource"
+	}
+	return kind
+}
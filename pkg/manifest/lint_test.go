@@ -0,0 +1,62 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	files := map[string]string{
+		"chart/templates/ok.yaml":        "kind: ConfigMap\nmetadata:\n  name: ok\n---\n# nothing rendered\n",
+		"chart/templates/noname.yaml":    "kind: ConfigMap\nmetadata:\n  labels:\n    app: foo\n",
+		"chart/templates/nokind.yaml":    "apiVersion: v1\nmetadata:\n  name: nokind\n",
+		"chart/templates/broken.yaml":    "kind: Pod\nmetadata: [unterminated",
+		"chart/templates/NOTES.txt":      "not a resource",
+		"chart/templates/_helpers.tpl":   "not a resource",
+		"chart/templates/multi.yaml":     "kind: Secret\nmetadata:\n  name: ok\n---\nkind: Secret\n",
+		"chart/templates/whitespace.yml": "  \n",
+	}
+
+	expect := []struct {
+		path   string
+		reason ManifestErrorReason
+		msg    string
+	}{
+		{"chart/templates/broken.yaml", ManifestParseError, ""},
+		{"chart/templates/multi.yaml", ManifestLintError, "Secret has no metadata.name"},
+		{"chart/templates/noname.yaml", ManifestLintError, "ConfigMap has no metadata.name"},
+		{"chart/templates/nokind.yaml", ManifestLintError, "resource has no kind"},
+	}
+
+	errs := Lint(files)
+	if len(errs) != len(expect) {
+		t.Fatalf("Expected %d errors, got %d: %v", len(expect), len(errs), errs)
+	}
+	for i, e := range expect {
+		if errs[i].Path != e.path || errs[i].Reason != e.reason {
+			t.Errorf("Expected a %s error on %s, got %s", e.reason, e.path, errs[i])
+		}
+		if e.msg != "" && errs[i].Err.Error() != e.msg {
+			t.Errorf("Expected %q, got %q", e.msg, errs[i].Err)
+		}
+	}
+
+	if errs := Lint(map[string]string{"chart/templates/ok.yaml": "kind: ConfigMap\nmetadata:\n  name: ok\n"}); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
@@ -0,0 +1,712 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	util "k8s.io/helm/pkg/releaseutil"
+)
+
+// Events maps the values of the helm.sh/hook annotation to hook events.
+var Events = map[string]release.Hook_Event{
+	hooks.PreInstall:         release.Hook_PRE_INSTALL,
+	hooks.PostInstall:        release.Hook_POST_INSTALL,
+	hooks.PreDelete:          release.Hook_PRE_DELETE,
+	hooks.PostDelete:         release.Hook_POST_DELETE,
+	hooks.PreUpgrade:         release.Hook_PRE_UPGRADE,
+	hooks.PostUpgrade:        release.Hook_POST_UPGRADE,
+	hooks.PreRollback:        release.Hook_PRE_ROLLBACK,
+	hooks.PostRollback:       release.Hook_POST_ROLLBACK,
+	hooks.ReleaseTestSuccess: release.Hook_RELEASE_TEST_SUCCESS,
+	hooks.ReleaseTestFailure: release.Hook_RELEASE_TEST_FAILURE,
+	hooks.CRDInstall:         release.Hook_CRD_INSTALL,
+	hooks.ReleaseTest:        release.Hook_RELEASE_TEST_SUCCESS,
+}
+
+// DeletePolicies represents a mapping between the key in the annotation for label deleting policy and its real meaning
+var DeletePolicies = map[string]release.Hook_DeletePolicy{
+	hooks.HookSucceeded:      release.Hook_SUCCEEDED,
+	hooks.HookFailed:         release.Hook_FAILED,
+	hooks.BeforeHookCreation: release.Hook_BEFORE_HOOK_CREATION,
+}
+
+// OutputLogPolicies maps the values of the helm.sh/hook-output-log-policy annotation to hook output log policies.
+var OutputLogPolicies = map[string]release.Hook_OutputLogPolicy{
+	hooks.HookOutputOnSucceeded: release.Hook_OUTPUT_ON_SUCCEEDED,
+	hooks.HookOutputOnFailed:    release.Hook_OUTPUT_ON_FAILED,
+}
+
+// Manifest represents a manifest file, which has a name and some content.
+type Manifest struct {
+	Name    string
+	Content string
+	Head    *util.SimpleHead
+	// Annotations are the metadata.annotations of the resource, if any.
+	Annotations map[string]string
+	// DocIndex is the position of the resource among the YAML documents of its
+	// file, counting from 0 and including documents that are hooks.
+	DocIndex int
+}
+
+// ToUnstructured parses the content of the manifest into an object that can
+// be used with a dynamic client.
+func (m Manifest) ToUnstructured() (*unstructured.Unstructured, error) {
+	if m.Head != nil && m.Head.Kind == "" {
+		return nil, &ManifestError{Path: m.Name, Reason: ManifestParseError, Err: errors.New("missing kind")}
+	}
+	data, err := yaml.YAMLToJSON([]byte(m.Content))
+	if err != nil {
+		return nil, &ManifestError{Path: m.Name, Reason: ManifestParseError, Err: err}
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return nil, &ManifestError{Path: m.Name, Reason: ManifestParseError, Err: err}
+	}
+	return obj, nil
+}
+
+// ManifestErrorReason describes the kind of problem found in a manifest.
+type ManifestErrorReason string
+
+// ManifestParseError indicates that a manifest could not be parsed as YAML.
+const ManifestParseError ManifestErrorReason = "parse"
+
+// ManifestPolicyError indicates that a manifest is of a kind denied with DenyKinds.
+const ManifestPolicyError ManifestErrorReason = "policy"
+
+// ManifestError is the error returned when a manifest file can not be sorted.
+//
+// Path is the name of the file that contained the offending manifest, and Err
+// is the underlying cause.
+type ManifestError struct {
+	Path   string
+	Reason ManifestErrorReason
+	Err    error
+}
+
+func (e *ManifestError) Error() string {
+	if e.Reason == ManifestParseError {
+		return fmt.Sprintf("YAML parse error on %s: %s", e.Path, e.Err)
+	}
+	return fmt.Sprintf("%s error on %s: %s", e.Reason, e.Path, e.Err)
+}
+
+// notesFile is the name of the template holding a chart's usage notes.
+const notesFile = "NOTES.txt"
+
+type result struct {
+	hooks   []*release.Hook
+	generic []Manifest
+	skipped []SkippedResource
+}
+
+// SkipReason describes why Partition skipped a file or resource.
+type SkipReason string
+
+const (
+	// SkipPartial indicates a partial template, whose name starts with an underscore.
+	SkipPartial SkipReason = "partial"
+	// SkipEmpty indicates a file that rendered to nothing but whitespace.
+	SkipEmpty SkipReason = "empty"
+	// SkipUnknownHook indicates a resource annotated with a hook Helm does not know.
+	SkipUnknownHook SkipReason = "unknown hook"
+)
+
+// SkippedResource records a file, or a resource within it, that Partition skipped.
+type SkippedResource struct {
+	Path   string
+	Reason SkipReason
+}
+
+type manifestFile struct {
+	entries             map[string]string
+	path                string
+	apis                chartutil.VersionSet
+	namespace           string
+	defaultDeletePolicy bool
+}
+
+// PartitionOption configures Partition.
+type PartitionOption func(*partitionOptions)
+
+type partitionOptions struct {
+	denied      map[string]bool
+	namespace   string
+	skipped     *[]SkippedResource
+	dedupeNotes bool
+	pathPrefix  string
+	// defaultDeletePolicy sets before-hook-creation on hooks without a delete policy
+	defaultDeletePolicy bool
+}
+
+// TargetNamespace sets the namespace of the parsed heads of namespaced
+// resources that do not set metadata.namespace themselves. Resources of
+// cluster-scoped kinds are left without a namespace.
+func TargetNamespace(namespace string) PartitionOption {
+	return func(opts *partitionOptions) {
+		opts.namespace = namespace
+	}
+}
+
+// CollectSkipped appends what Partition skipped to skipped, ordered by path.
+// Skipped files and resources are logged either way.
+func CollectSkipped(skipped *[]SkippedResource) PartitionOption {
+	return func(opts *partitionOptions) {
+		opts.skipped = skipped
+	}
+}
+
+// DedupeNotes collapses NOTES.txt files with the same content, such as those
+// rendered from a shared partial, into the entry of the chart nearest to the
+// top of the chart tree. By default every chart has its own entry.
+func DedupeNotes(dedupe bool) PartitionOption {
+	return func(opts *partitionOptions) {
+		opts.dedupeNotes = dedupe
+	}
+}
+
+// PathPrefix limits Partition to the files whose path starts with prefix, and
+// ignores the others. For instance "parent/templates/" scopes a render of an
+// umbrella chart to the parent chart, as subcharts render to "parent/charts/".
+func PathPrefix(prefix string) PartitionOption {
+	return func(opts *partitionOptions) {
+		opts.pathPrefix = prefix
+	}
+}
+
+// DefaultDeletePolicy gives hooks without a helm.sh/hook-delete-policy
+// annotation the before-hook-creation policy, so that Tiller deletes the hook
+// left by a previous release before creating it again. Tiller only deletes hooks
+// with an explicit policy, so by default their DeletePolicies are left empty
+// and such hooks are never deleted.
+func DefaultDeletePolicy(enabled bool) PartitionOption {
+	return func(opts *partitionOptions) {
+		opts.defaultDeletePolicy = enabled
+	}
+}
+
+// collectSkipped appends what was skipped for result to the collector given with CollectSkipped, if any.
+func (po partitionOptions) collectSkipped(result *result) {
+	if po.skipped == nil {
+		return
+	}
+	sort.SliceStable(result.skipped, func(i, j int) bool { return result.skipped[i].Path < result.skipped[j].Path })
+	*po.skipped = append(*po.skipped, result.skipped...)
+}
+
+// clusterScoped are the built-in kinds that do not live in a namespace.
+var clusterScoped = map[string]bool{
+	"APIService":                     true,
+	"CertificateSigningRequest":      true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CustomResourceDefinition":       true,
+	"MutatingWebhookConfiguration":   true,
+	"Namespace":                      true,
+	"Node":                           true,
+	"PersistentVolume":               true,
+	"PodSecurityPolicy":              true,
+	"PriorityClass":                  true,
+	"StorageClass":                   true,
+	"ValidatingWebhookConfiguration": true,
+	"VolumeAttachment":               true,
+}
+
+// DenyKinds makes Partition fail if any hook or manifest is of one of the given
+// kinds, e.g. ClusterRoleBinding, so that a policy can be enforced before
+// anything reaches the cluster.
+func DenyKinds(kinds ...string) PartitionOption {
+	return func(opts *partitionOptions) {
+		if opts.denied == nil {
+			opts.denied = map[string]bool{}
+		}
+		for _, kind := range kinds {
+			opts.denied[kind] = true
+		}
+	}
+}
+
+// checkDenied returns a ManifestError for the first file, by path, holding a
+// resource of a denied kind.
+func (po partitionOptions) checkDenied(result *result) error {
+	var found *ManifestError
+	deny := func(kind, path string) {
+		if po.denied[kind] && (found == nil || path < found.Path) {
+			found = &ManifestError{Path: path, Reason: ManifestPolicyError, Err: fmt.Errorf("kind %s is denied", kind)}
+		}
+	}
+	for _, h := range result.hooks {
+		deny(h.Kind, h.Path)
+	}
+	for _, m := range result.generic {
+		deny(m.Head.Kind, m.Name)
+	}
+	if found == nil {
+		return nil
+	}
+	return found
+}
+
+// Partition takes a map of filename/YAML contents, splits the file
+// by manifest entries, and sorts the entries into hook types.
+//
+// The resulting hooks will be populated with all of the generated hooks.
+// Any file that does not declare one of the hook types will be placed in the
+// generic manifests, which are sorted by kind using the given SortOrder.
+//
+// NOTES.txt files are neither hooks nor resources. They are returned
+// separately, keyed by the path of the chart directory they were rendered
+// from, such as "parent" or "parent/charts/sub", so that a subchart named like
+// its parent does not collide with it. Partials, empty files and resources with unknown hooks are skipped,
+// which is logged and can be collected with CollectSkipped.
+func Partition(files map[string]string, apis chartutil.VersionSet, sort SortOrder, opts ...PartitionOption) ([]*release.Hook, []Manifest, map[string]string, error) {
+	po := partitionOptions{}
+	for _, opt := range opts {
+		opt(&po)
+	}
+
+	result := &result{}
+	notes := map[string]string{}
+	defer po.collectSkipped(result)
+
+	for filePath, c := range files {
+		if !strings.HasPrefix(filePath, po.pathPrefix) {
+			continue
+		}
+
+		// Skip partials. We could return these as a separate map, but there doesn't
+		// seem to be any need for that at this time.
+		if strings.HasPrefix(path.Base(filePath), "_") {
+			result.skipped = append(result.skipped, SkippedResource{Path: filePath, Reason: SkipPartial})
+			continue
+		}
+		if strings.HasSuffix(filePath, notesFile) {
+			notes[chartPath(filePath)] = c
+			continue
+		}
+		// Skip empty files and log this.
+		if len(strings.TrimSpace(c)) == 0 {
+			log.Printf("info: manifest %q is empty. Skipping.", filePath)
+			result.skipped = append(result.skipped, SkippedResource{Path: filePath, Reason: SkipEmpty})
+			continue
+		}
+
+		manifestFile := &manifestFile{
+			entries:             util.SplitManifests(c),
+			path:                filePath,
+			apis:                apis,
+			namespace:           po.namespace,
+			defaultDeletePolicy: po.defaultDeletePolicy,
+		}
+
+		if err := manifestFile.sort(result); err != nil {
+			return result.hooks, result.generic, notes, err
+		}
+	}
+	if err := po.checkDenied(result); err != nil {
+		return result.hooks, result.generic, notes, err
+	}
+
+	if po.dedupeNotes {
+		dedupeNotes(notes)
+	}
+	return result.hooks, sortByKind(result.generic, sort), notes, nil
+}
+
+// dedupeNotes removes from notes every entry with the same content as that of a
+// chart nearer to the top of the chart tree. Between charts at the same depth,
+// the first by path is kept.
+func dedupeNotes(notes map[string]string) {
+	depth := func(chart string) int {
+		return strings.Count(chart, "/charts/")
+	}
+	kept := map[string]string{}
+	for chart, content := range notes {
+		other, ok := kept[content]
+		if !ok {
+			kept[content] = chart
+			continue
+		}
+		if depth(chart) < depth(other) || (depth(chart) == depth(other) && chart < other) {
+			kept[content] = chart
+			delete(notes, other)
+		} else {
+			delete(notes, chart)
+		}
+	}
+}
+
+// CombineNotes joins the notes returned by Partition into a single string,
+// each under a "==> <chart path>" header. The chart paths in order come first,
+// in that order, followed by any others sorted by path. Charts in order without
+// notes are left out.
+func CombineNotes(notes map[string]string, order []string) string {
+	charts := []string{}
+	seen := map[string]bool{}
+	for _, chart := range order {
+		if _, ok := notes[chart]; ok && !seen[chart] {
+			charts = append(charts, chart)
+			seen[chart] = true
+		}
+	}
+	rest := []string{}
+	for chart := range notes {
+		if !seen[chart] {
+			rest = append(rest, chart)
+		}
+	}
+	sort.Strings(rest)
+
+	sections := make([]string, 0, len(notes))
+	for _, chart := range append(charts, rest...) {
+		sections = append(sections, "==> "+chart+"\n"+strings.TrimSpace(notes[chart]))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// PartitionBundle holds the results of Partition, so that they can be passed
+// around together.
+type PartitionBundle struct {
+	Hooks     []*release.Hook
+	Manifests []Manifest
+	// Notes are the rendered NOTES.txt files, keyed by chart path.
+	Notes map[string]string
+}
+
+// PartitionToBundle is Partition, returning its results as a PartitionBundle.
+// On error, the bundle holds whatever was partitioned before the error.
+func PartitionToBundle(files map[string]string, apis chartutil.VersionSet, sort SortOrder, opts ...PartitionOption) (*PartitionBundle, error) {
+	hooks, manifests, notes, err := Partition(files, apis, sort, opts...)
+	return &PartitionBundle{Hooks: hooks, Manifests: manifests, Notes: notes}, err
+}
+
+// MergePartitions combines the results of two calls to Partition, for example
+// of a chart and of extra manifests rendered separately. Hooks of a come before
+// those of b, and the manifests of both are sorted together in InstallOrder.
+//
+// It returns an error if a and b both hold a hook or manifest for the same
+// resource, or notes for the same chart. Neither bundle is modified.
+func MergePartitions(a, b PartitionBundle) (PartitionBundle, error) {
+	merged := PartitionBundle{
+		Hooks:     make([]*release.Hook, 0, len(a.Hooks)+len(b.Hooks)),
+		Manifests: make([]Manifest, 0, len(a.Manifests)+len(b.Manifests)),
+		Notes:     make(map[string]string, len(a.Notes)+len(b.Notes)),
+	}
+
+	resources := map[string]bool{}
+	for _, h := range append(append([]*release.Hook{}, a.Hooks...), b.Hooks...) {
+		key := "hook " + h.Kind + "/" + h.Name
+		if resources[key] {
+			return PartitionBundle{}, fmt.Errorf("duplicate %s in %s", key, h.Path)
+		}
+		resources[key] = true
+		merged.Hooks = append(merged.Hooks, h)
+	}
+	for _, m := range append(append([]Manifest{}, a.Manifests...), b.Manifests...) {
+		key := resourceKey(m)
+		if resources[key] {
+			return PartitionBundle{}, fmt.Errorf("duplicate %s in %s", key, m.Name)
+		}
+		resources[key] = true
+		merged.Manifests = append(merged.Manifests, m)
+	}
+	for _, notes := range []map[string]string{a.Notes, b.Notes} {
+		for chart, n := range notes {
+			if _, ok := merged.Notes[chart]; ok {
+				return PartitionBundle{}, fmt.Errorf("duplicate notes for chart %s", chart)
+			}
+			merged.Notes[chart] = n
+		}
+	}
+
+	merged.Manifests = sortByKind(merged.Manifests, InstallOrder)
+	return merged, nil
+}
+
+// resourceKey identifies the resource of m as kind/namespace/name, leaving out
+// the namespace if it has none.
+func resourceKey(m Manifest) string {
+	if m.Head == nil {
+		return "manifest " + m.Name
+	}
+	key := m.Head.Kind + "/"
+	if m.Head.Metadata != nil {
+		if m.Head.Metadata.Namespace != "" {
+			key += m.Head.Metadata.Namespace + "/"
+		}
+		key += m.Head.Metadata.Name
+	}
+	return key
+}
+
+// chartPath returns the path of the chart directory a template was rendered
+// from, e.g. "parent/charts/sub" for "parent/charts/sub/templates/NOTES.txt".
+func chartPath(filePath string) string {
+	return path.Dir(path.Dir(filePath))
+}
+
+// ClassifyManifest splits a single rendered template into its hooks and generic
+// manifests, using the same rules as Partition does for a whole chart.
+//
+// Generic manifests are returned sorted in InstallOrder. Since content has no
+// file name, the Path of the hooks and the Name of the manifests are left empty.
+func ClassifyManifest(content string, apis chartutil.VersionSet) ([]*release.Hook, []Manifest, error) {
+	result := &result{}
+	file := &manifestFile{
+		entries: util.SplitManifests(content),
+		apis:    apis,
+	}
+	if err := file.sort(result); err != nil {
+		return result.hooks, result.generic, err
+	}
+	return result.hooks, sortByKind(result.generic, InstallOrder), nil
+}
+
+// HooksToFiles assembles hooks back into the files they were partitioned from,
+// so that they can be passed to Partition again. Hooks from the same file are
+// joined as separate YAML documents, in the order they are given. Hooks without
+// a Path, such as those returned by ClassifyManifest, are all collected under "".
+func HooksToFiles(hooks []*release.Hook) map[string]string {
+	files := map[string]string{}
+	for _, h := range hooks {
+		if content, ok := files[h.Path]; ok {
+			files[h.Path] = content + "\n---\n" + h.Manifest
+			continue
+		}
+		files[h.Path] = h.Manifest
+	}
+	return files
+}
+
+// sort takes a manifestFile object which may contain multiple resource definition
+// entries and sorts each entry by hook types, and saves the resulting hooks and
+// generic manifests (or non-hooks) to the result struct.
+//
+// To determine hook type, it looks for a YAML structure like this:
+//
+//  kind: SomeKind
+//  apiVersion: v1
+// 	metadata:
+//		annotations:
+//			helm.sh/hook: pre-install
+//
+// To determine the policy to delete the hook, it looks for a YAML structure like this:
+//
+//  kind: SomeKind
+//  apiVersion: v1
+//  metadata:
+// 		annotations:
+// 			helm.sh/hook-delete-policy: hook-succeeded
+func (file *manifestFile) sort(result *result) error {
+	// Entries are visited in document order, so that each manifest knows its position
+	for i := 0; i < len(file.entries); i++ {
+		m := file.entries[fmt.Sprintf("manifest-%d", i)]
+		var entry util.SimpleHead
+		err := yaml.Unmarshal([]byte(m), &entry)
+
+		if err != nil {
+			return &ManifestError{Path: file.path, Reason: ManifestParseError, Err: err}
+		}
+
+		if file.namespace != "" && entry.Metadata != nil && entry.Metadata.Namespace == "" && !clusterScoped[entry.Kind] {
+			entry.Metadata.Namespace = file.namespace
+		}
+
+		if !hasAnyAnnotation(entry) {
+			result.generic = append(result.generic, Manifest{
+				Name:     file.path,
+				Content:  m,
+				Head:     &entry,
+				DocIndex: i,
+			})
+			continue
+		}
+
+		hookTypes, ok := entry.Metadata.Annotations[hooks.HookAnno]
+		if !ok {
+			result.generic = append(result.generic, Manifest{
+				Name:        file.path,
+				Content:     m,
+				Head:        &entry,
+				Annotations: entry.Metadata.Annotations,
+				DocIndex:    i,
+			})
+			continue
+		}
+
+		// An invalid weight is treated as 0, as it always has been
+		hw, _ := HookWeight(entry.Metadata.Annotations)
+		timeout, err := HookTimeout(entry.Metadata.Annotations)
+		if err != nil {
+			log.Printf("warning: %s: %s, using the release timeout", file.path, err)
+		}
+
+		h := &release.Hook{
+			Name:              entry.Metadata.Name,
+			Kind:              entry.Kind,
+			Path:              file.path,
+			Manifest:          m,
+			Events:            []release.Hook_Event{},
+			Weight:            hw,
+			DeletePolicies:    HookDeletePolicies(entry.Metadata.Annotations),
+			OutputLogPolicies: HookOutputLogPolicies(entry.Metadata.Annotations),
+			Timeout:           timeout,
+		}
+		if _, ok := entry.Metadata.Annotations[hooks.HookDeleteAnno]; !ok && file.defaultDeletePolicy {
+			h.DeletePolicies = []release.Hook_DeletePolicy{release.Hook_BEFORE_HOOK_CREATION}
+		}
+
+		isUnknownHook := false
+		for _, hookType := range strings.Split(hookTypes, ",") {
+			hookType = strings.ToLower(strings.TrimSpace(hookType))
+			e, ok := Events[hookType]
+			if !ok {
+				isUnknownHook = true
+				break
+			}
+			if hookType == hooks.ReleaseTest {
+				log.Printf("warning: %s uses the deprecated %q hook, use %q instead", file.path, hooks.ReleaseTest, hooks.ReleaseTestSuccess)
+			}
+			if !hasEvent(h, e) {
+				h.Events = append(h.Events, e)
+			}
+		}
+
+		if isUnknownHook {
+			log.Printf("info: skipping unknown hook: %q", hookTypes)
+			result.skipped = append(result.skipped, SkippedResource{Path: file.path, Reason: SkipUnknownHook})
+			continue
+		}
+
+		result.hooks = append(result.hooks, h)
+	}
+	return nil
+}
+
+func hasEvent(h *release.Hook, event release.Hook_Event) bool {
+	for _, e := range h.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyAnnotation(entry util.SimpleHead) bool {
+	if entry.Metadata == nil ||
+		entry.Metadata.Annotations == nil ||
+		len(entry.Metadata.Annotations) == 0 {
+		return false
+	}
+
+	return true
+}
+
+// HookWeight parses the helm.sh/hook-weight annotation. A missing weight is 0.
+// A weight that is not an integer is an error; Partition treats it as 0.
+func HookWeight(annotations map[string]string) (int32, error) {
+	hws, ok := annotations[hooks.HookWeightAnno]
+	if !ok {
+		return 0, nil
+	}
+	hw, err := strconv.ParseInt(hws, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hook weight %q: %s", hws, err)
+	}
+	return int32(hw), nil
+}
+
+// HookTimeout parses the helm.sh/hook-timeout annotation, in seconds. A missing
+// timeout is 0, meaning the timeout of the release applies. A timeout that is
+// not a positive integer is an error; Partition treats it as 0.
+func HookTimeout(annotations map[string]string) (int64, error) {
+	ts, ok := annotations[hooks.HookTimeoutAnno]
+	if !ok {
+		return 0, nil
+	}
+	t, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hook timeout %q: %s", ts, err)
+	}
+	if t <= 0 {
+		return 0, fmt.Errorf("invalid hook timeout %q: must be a positive number of seconds", ts)
+	}
+	return t, nil
+}
+
+// HookDeletePolicies parses the helm.sh/hook-delete-policy annotation. Unknown
+// policies are logged and skipped, and duplicates are only returned once.
+func HookDeletePolicies(annotations map[string]string) []release.Hook_DeletePolicy {
+	policies := []release.Hook_DeletePolicy{}
+	operateAnnotationValues(annotations, hooks.HookDeleteAnno, func(value string) {
+		policy, exist := DeletePolicies[value]
+		if !exist {
+			log.Printf("info: skipping unknown hook delete policy: %q", value)
+			return
+		}
+		for _, p := range policies {
+			if p == policy {
+				return
+			}
+		}
+		policies = append(policies, policy)
+	})
+	return policies
+}
+
+// HookOutputLogPolicies parses the helm.sh/hook-output-log-policy annotation.
+// Without it the logs of the hook are never captured. Unknown policies are
+// logged and skipped, and duplicates are only returned once.
+func HookOutputLogPolicies(annotations map[string]string) []release.Hook_OutputLogPolicy {
+	policies := []release.Hook_OutputLogPolicy{}
+	operateAnnotationValues(annotations, hooks.HookOutputLogAnno, func(value string) {
+		policy, exist := OutputLogPolicies[value]
+		if !exist {
+			log.Printf("info: skipping unknown hook output log policy: %q", value)
+			return
+		}
+		for _, p := range policies {
+			if p == policy {
+				return
+			}
+		}
+		policies = append(policies, policy)
+	})
+	return policies
+}
+
+func operateAnnotationValues(annotations map[string]string, annotation string, operate func(p string)) {
+	if dps, ok := annotations[annotation]; ok {
+		for _, dp := range strings.Split(dps, ",") {
+			dp = strings.ToLower(strings.TrimSpace(dp))
+			operate(dp)
+		}
+	}
+}
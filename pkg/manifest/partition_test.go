@@ -0,0 +1,1218 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	util "k8s.io/helm/pkg/releaseutil"
+)
+
+func TestPartition(t *testing.T) {
+
+	data := []struct {
+		name     []string
+		path     string
+		kind     []string
+		hooks    map[string][]release.Hook_Event
+		manifest string
+	}{
+		{
+			name:  []string{"first"},
+			path:  "one",
+			kind:  []string{"Job"},
+			hooks: map[string][]release.Hook_Event{"first": {release.Hook_PRE_INSTALL}},
+			manifest: `apiVersion: v1
+kind: Job
+metadata:
+  name: first
+  labels:
+    doesnot: matter
+  annotations:
+    "helm.sh/hook": pre-install
+`,
+		},
+		{
+			name:  []string{"second"},
+			path:  "two",
+			kind:  []string{"ReplicaSet"},
+			hooks: map[string][]release.Hook_Event{"second": {release.Hook_POST_INSTALL}},
+			manifest: `kind: ReplicaSet
+apiVersion: v1beta1
+metadata:
+  name: second
+  annotations:
+    "helm.sh/hook": post-install
+`,
+		}, {
+			name:  []string{"third"},
+			path:  "three",
+			kind:  []string{"ReplicaSet"},
+			hooks: map[string][]release.Hook_Event{"third": nil},
+			manifest: `kind: ReplicaSet
+apiVersion: v1beta1
+metadata:
+  name: third
+  annotations:
+    "helm.sh/hook": no-such-hook
+`,
+		}, {
+			name:  []string{"fourth"},
+			path:  "four",
+			kind:  []string{"Pod"},
+			hooks: map[string][]release.Hook_Event{"fourth": nil},
+			manifest: `kind: Pod
+apiVersion: v1
+metadata:
+  name: fourth
+  annotations:
+    nothing: here`,
+		}, {
+			name:  []string{"fifth"},
+			path:  "five",
+			kind:  []string{"ReplicaSet"},
+			hooks: map[string][]release.Hook_Event{"fifth": {release.Hook_POST_DELETE, release.Hook_POST_INSTALL}},
+			manifest: `kind: ReplicaSet
+apiVersion: v1beta1
+metadata:
+  name: fifth
+  annotations:
+    "helm.sh/hook": post-delete, post-install
+`,
+		}, {
+			// Regression test: files with an underscore in the base name should be skipped.
+			name:     []string{"sixth"},
+			path:     "six/_six",
+			kind:     []string{"ReplicaSet"},
+			hooks:    map[string][]release.Hook_Event{"sixth": nil},
+			manifest: `invalid manifest`, // This will fail if partial is not skipped.
+		}, {
+			// Regression test: files with no content should be skipped.
+			name:     []string{"seventh"},
+			path:     "seven",
+			kind:     []string{"ReplicaSet"},
+			hooks:    map[string][]release.Hook_Event{"seventh": nil},
+			manifest: "",
+		},
+		{
+			name:  []string{"eighth", "example-test"},
+			path:  "eight",
+			kind:  []string{"ConfigMap", "Pod"},
+			hooks: map[string][]release.Hook_Event{"eighth": nil, "example-test": {release.Hook_RELEASE_TEST_SUCCESS}},
+			manifest: `kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: eighth
+data:
+  name: value
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: example-test
+  annotations:
+    "helm.sh/hook": test-success
+`,
+		},
+	}
+
+	manifests := make(map[string]string, len(data))
+	for _, o := range data {
+		manifests[o.path] = o.manifest
+	}
+
+	hs, generic, _, err := Partition(manifests, chartutil.NewVersionSet("v1", "v1beta1"), InstallOrder)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// This test will fail if 'six' or 'seven' was added.
+	if len(generic) != 2 {
+		t.Errorf("Expected 2 generic manifests, got %d", len(generic))
+	}
+
+	if len(hs) != 4 {
+		t.Errorf("Expected 4 hooks, got %d", len(hs))
+	}
+
+	for _, out := range hs {
+		found := false
+		for _, expect := range data {
+			if out.Path == expect.path {
+				found = true
+				if out.Path != expect.path {
+					t.Errorf("Expected path %s, got %s", expect.path, out.Path)
+				}
+				nameFound := false
+				for _, expectedName := range expect.name {
+					if out.Name == expectedName {
+						nameFound = true
+					}
+				}
+				if !nameFound {
+					t.Errorf("Got unexpected name %s", out.Name)
+				}
+				kindFound := false
+				for _, expectedKind := range expect.kind {
+					if out.Kind == expectedKind {
+						kindFound = true
+					}
+				}
+				if !kindFound {
+					t.Errorf("Got unexpected kind %s", out.Kind)
+				}
+
+				expectedHooks := expect.hooks[out.Name]
+				if !reflect.DeepEqual(expectedHooks, out.Events) {
+					t.Errorf("expected events: %v but got: %v", expectedHooks, out.Events)
+				}
+
+			}
+		}
+		if !found {
+			t.Errorf("Result not found: %v", out)
+		}
+	}
+
+	// Verify the sort order
+	sorted := []Manifest{}
+	for _, s := range data {
+		manifests := util.SplitManifests(s.manifest)
+
+		for _, m := range manifests {
+			var sh util.SimpleHead
+			err := yaml.Unmarshal([]byte(m), &sh)
+			if err != nil {
+				// This is expected for manifests that are corrupt or empty.
+				t.Log(err)
+				continue
+			}
+
+			name := sh.Metadata.Name
+
+			//only keep track of non-hook manifests
+			if err == nil && s.hooks[name] == nil {
+				another := Manifest{
+					Content: m,
+					Name:    name,
+					Head:    &sh,
+				}
+				sorted = append(sorted, another)
+			}
+		}
+	}
+
+	sorted = sortByKind(sorted, InstallOrder)
+	for i, m := range generic {
+		if m.Content != sorted[i].Content {
+			t.Errorf("Expected %q, got %q", m.Content, sorted[i].Content)
+		}
+	}
+}
+
+func TestPartitionParseError(t *testing.T) {
+	manifests := map[string]string{
+		"templates/broken.yaml": "kind: Pod\nmetadata: [unterminated",
+	}
+
+	_, _, _, err := Partition(manifests, chartutil.NewVersionSet("v1"), InstallOrder)
+	if err == nil {
+		t.Fatal("Expected a parse error")
+	}
+
+	merr, ok := err.(*ManifestError)
+	if !ok {
+		t.Fatalf("Expected *ManifestError, got %T", err)
+	}
+	if merr.Reason != ManifestParseError {
+		t.Errorf("Expected reason %q, got %q", ManifestParseError, merr.Reason)
+	}
+	if merr.Path != "templates/broken.yaml" {
+		t.Errorf("Expected path templates/broken.yaml, got %q", merr.Path)
+	}
+	if merr.Err == nil {
+		t.Error("Expected a wrapped cause")
+	}
+	if !strings.HasPrefix(err.Error(), "YAML parse error on templates/broken.yaml: ") {
+		t.Errorf("Unexpected error message: %s", err)
+	}
+}
+
+func TestPartitionDeletePolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		expect []release.Hook_DeletePolicy
+	}{
+		{"succeeded", "hook-succeeded", []release.Hook_DeletePolicy{release.Hook_SUCCEEDED}},
+		{"failed", "hook-failed", []release.Hook_DeletePolicy{release.Hook_FAILED}},
+		{"before creation", "before-hook-creation", []release.Hook_DeletePolicy{release.Hook_BEFORE_HOOK_CREATION}},
+		{
+			"succeeded and failed",
+			"hook-succeeded,hook-failed",
+			[]release.Hook_DeletePolicy{release.Hook_SUCCEEDED, release.Hook_FAILED},
+		},
+		{
+			"all three",
+			"before-hook-creation, hook-succeeded ,HOOK-FAILED",
+			[]release.Hook_DeletePolicy{release.Hook_BEFORE_HOOK_CREATION, release.Hook_SUCCEEDED, release.Hook_FAILED},
+		},
+		{
+			"duplicates and unknown",
+			"hook-failed,hook-failed,no-such-policy",
+			[]release.Hook_DeletePolicy{release.Hook_FAILED},
+		},
+	}
+
+	for _, tt := range tests {
+		manifests := map[string]string{
+			"templates/hook.yaml": `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: hook
+  annotations:
+    "helm.sh/hook": pre-install
+    "helm.sh/hook-delete-policy": "` + tt.policy + `"
+`,
+		}
+		hs, _, _, err := Partition(manifests, chartutil.NewVersionSet("v1"), InstallOrder)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if len(hs) != 1 {
+			t.Fatalf("%s: expected 1 hook, got %d", tt.name, len(hs))
+		}
+		if !reflect.DeepEqual(hs[0].DeletePolicies, tt.expect) {
+			t.Errorf("%s: expected policies %v, got %v", tt.name, tt.expect, hs[0].DeletePolicies)
+		}
+		for _, p := range []release.Hook_DeletePolicy{release.Hook_SUCCEEDED, release.Hook_FAILED, release.Hook_BEFORE_HOOK_CREATION} {
+			want := false
+			for _, e := range tt.expect {
+				want = want || e == p
+			}
+			if got := hooks.HasDeletePolicy(hs[0], p); got != want {
+				t.Errorf("%s: expected HasDeletePolicy(%s)=%t, got %t", tt.name, p, want, got)
+			}
+		}
+	}
+}
+
+func TestPartitionTestEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		hook   string
+		expect []release.Hook_Event
+	}{
+		{"success", "test-success", []release.Hook_Event{release.Hook_RELEASE_TEST_SUCCESS}},
+		{"failure", "test-failure", []release.Hook_Event{release.Hook_RELEASE_TEST_FAILURE}},
+		{"legacy alias", "test", []release.Hook_Event{release.Hook_RELEASE_TEST_SUCCESS}},
+		{"alias and success", "test,test-success", []release.Hook_Event{release.Hook_RELEASE_TEST_SUCCESS}},
+		{"alias and failure", "test, test-failure", []release.Hook_Event{release.Hook_RELEASE_TEST_SUCCESS, release.Hook_RELEASE_TEST_FAILURE}},
+	}
+
+	for _, tt := range tests {
+		manifests := map[string]string{
+			"templates/test.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: test
+  annotations:
+    "helm.sh/hook": ` + tt.hook + `
+`,
+		}
+		hs, _, _, err := Partition(manifests, chartutil.NewVersionSet("v1"), InstallOrder)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if len(hs) != 1 {
+			t.Fatalf("%s: expected 1 hook, got %d", tt.name, len(hs))
+		}
+		if !reflect.DeepEqual(hs[0].Events, tt.expect) {
+			t.Errorf("%s: expected events %v, got %v", tt.name, tt.expect, hs[0].Events)
+		}
+	}
+}
+
+func TestHooksToFiles(t *testing.T) {
+	files := map[string]string{
+		"chart/templates/jobs.yaml": `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: first
+  annotations:
+    "helm.sh/hook": pre-install
+    "helm.sh/hook-weight": "1"
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: second
+  annotations:
+    "helm.sh/hook": post-install,post-upgrade
+    "helm.sh/hook-delete-policy": hook-succeeded
+`,
+		"chart/templates/test.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: tester
+  annotations:
+    "helm.sh/hook": test-success
+`,
+		"chart/templates/cm.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+`,
+	}
+	apis := chartutil.NewVersionSet("v1", "batch/v1")
+
+	hs, _, _, err := Partition(files, apis, InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assembled := HooksToFiles(hs)
+	if len(assembled) != 2 {
+		t.Fatalf("Expected the hooks of 2 files, got %v", assembled)
+	}
+	if _, ok := assembled["chart/templates/cm.yaml"]; ok {
+		t.Error("Expected no file without hooks")
+	}
+
+	again, manifests, _, err := Partition(assembled, apis, InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 0 {
+		t.Errorf("Expected no manifests, got %v", manifests)
+	}
+	byName := func(hooks []*release.Hook) map[string]*release.Hook {
+		m := map[string]*release.Hook{}
+		for _, h := range hooks {
+			m[h.Name] = h
+		}
+		return m
+	}
+	if !reflect.DeepEqual(byName(again), byName(hs)) {
+		t.Errorf("Expected hooks %v after a round trip, got %v", hs, again)
+	}
+
+	if got := HooksToFiles([]*release.Hook{{Manifest: "a"}, {Manifest: "b"}}); got[""] != "a\n---\nb" {
+		t.Errorf("Expected hooks without a path to be joined, got %q", got)
+	}
+}
+
+func TestCombineNotes(t *testing.T) {
+	notes := map[string]string{
+		"parent":              "parent notes\n",
+		"parent/charts/child": "child notes",
+		"parent/charts/other": "other notes",
+		"parent/charts/alpha": "alpha notes",
+	}
+
+	tests := []struct {
+		name   string
+		order  []string
+		expect string
+	}{
+		{
+			name:   "no order",
+			expect: "==> parent\nparent notes\n\n==> parent/charts/alpha\nalpha notes\n\n==> parent/charts/child\nchild notes\n\n==> parent/charts/other\nother notes",
+		},
+		{
+			name:   "child then parent",
+			order:  []string{"parent/charts/child", "parent"},
+			expect: "==> parent/charts/child\nchild notes\n\n==> parent\nparent notes\n\n==> parent/charts/alpha\nalpha notes\n\n==> parent/charts/other\nother notes",
+		},
+		{
+			name:   "unknown and repeated charts",
+			order:  []string{"missing", "parent/charts/other", "parent", "parent/charts/other"},
+			expect: "==> parent/charts/other\nother notes\n\n==> parent\nparent notes\n\n==> parent/charts/alpha\nalpha notes\n\n==> parent/charts/child\nchild notes",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := CombineNotes(notes, tt.order); got != tt.expect {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expect, got)
+		}
+	}
+
+	if got := CombineNotes(nil, []string{"parent"}); got != "" {
+		t.Errorf("Expected no notes, got %q", got)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	content := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    "helm.sh/hook": pre-upgrade
+    "helm.sh/hook-weight": "5"
+`
+
+	hs, generic, err := ClassifyManifest(content, chartutil.NewVersionSet("v1", "batch/v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hs) != 1 {
+		t.Fatalf("Expected 1 hook, got %d", len(hs))
+	}
+	if hs[0].Name != "migrate" || hs[0].Kind != "Job" || hs[0].Weight != 5 {
+		t.Errorf("Unexpected hook: %+v", hs[0])
+	}
+	if !reflect.DeepEqual(hs[0].Events, []release.Hook_Event{release.Hook_PRE_UPGRADE}) {
+		t.Errorf("Expected pre-upgrade event, got %v", hs[0].Events)
+	}
+
+	if len(generic) != 1 {
+		t.Fatalf("Expected 1 generic manifest, got %d", len(generic))
+	}
+	if generic[0].Head.Kind != "ConfigMap" || generic[0].Head.Metadata.Name != "settings" {
+		t.Errorf("Unexpected manifest: %+v", generic[0].Head)
+	}
+
+	if _, _, err := ClassifyManifest("kind: Pod\nmetadata: [unterminated", chartutil.NewVersionSet("v1")); err == nil {
+		t.Error("Expected a parse error")
+	}
+}
+
+func TestPartitionDenyKinds(t *testing.T) {
+	files := map[string]string{
+		"chart/templates/cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n",
+		"chart/templates/rbac.yaml": `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: admin
+`,
+		"chart/templates/psp-hook.yaml": `apiVersion: extensions/v1beta1
+kind: PodSecurityPolicy
+metadata:
+  name: open
+  annotations:
+    "helm.sh/hook": pre-install
+`,
+	}
+	apis := chartutil.NewVersionSet("v1")
+	deny := DenyKinds("ClusterRoleBinding", "PodSecurityPolicy")
+
+	allowed := map[string]string{"chart/templates/cm.yaml": files["chart/templates/cm.yaml"]}
+	if _, manifests, _, err := Partition(allowed, apis, InstallOrder, deny); err != nil || len(manifests) != 1 {
+		t.Errorf("Expected the allowed chart to partition, got %v and %v", manifests, err)
+	}
+
+	_, _, _, err := Partition(files, apis, InstallOrder, deny)
+	merr, ok := err.(*ManifestError)
+	if !ok {
+		t.Fatalf("Expected a *ManifestError, got %v", err)
+	}
+	if merr.Reason != ManifestPolicyError || merr.Path != "chart/templates/psp-hook.yaml" {
+		t.Errorf("Expected a policy error for the first denied file, got %+v", merr)
+	}
+	if !strings.Contains(err.Error(), "PodSecurityPolicy") {
+		t.Errorf("Expected the error to name the denied kind, got %q", err)
+	}
+
+	delete(files, "chart/templates/psp-hook.yaml")
+	expect := "policy error on chart/templates/rbac.yaml: kind ClusterRoleBinding is denied"
+	if _, _, _, err := Partition(files, apis, InstallOrder, deny); err == nil || err.Error() != expect {
+		t.Errorf("Expected %q, got %v", expect, err)
+	}
+
+	if _, _, _, err := Partition(files, apis, InstallOrder); err != nil {
+		t.Errorf("Expected no kinds to be denied by default, got %s", err)
+	}
+}
+
+func TestVersionSet(t *testing.T) {
+	vs := chartutil.NewVersionSet("v1", "v1beta1", "extensions/alpha5", "batch/v1")
+
+	if l := len(vs); l != 4 {
+		t.Errorf("Expected 4, got %d", l)
+	}
+
+	if !vs.Has("extensions/alpha5") {
+		t.Error("No match for alpha5")
+	}
+
+	if vs.Has("nosuch/extension") {
+		t.Error("Found nonexistent extension")
+	}
+}
+
+func TestPartitionNotes(t *testing.T) {
+	files := map[string]string{
+		"parent/templates/NOTES.txt":              "parent notes",
+		"parent/charts/child/templates/NOTES.txt": "child notes",
+		"parent/templates/cm.yaml":                "kind: ConfigMap\nmetadata:\n  name: cm",
+	}
+
+	_, generic, notes, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]string{"parent": "parent notes", "child": "child notes"}
+	if !reflect.DeepEqual(notes, expect) {
+		t.Errorf("Expected notes %v, got %v", expect, notes)
+	}
+	if len(generic) != 1 || generic[0].Name != "parent/templates/cm.yaml" {
+		t.Errorf("Expected only the ConfigMap manifest, got %v", generic)
+	}
+}
+
+func TestPartitionDedupeNotes(t *testing.T) {
+	files := map[string]string{
+		"parent/templates/NOTES.txt":                "shared notes",
+		"parent/charts/child/templates/NOTES.txt":   "shared notes",
+		"parent/charts/another/templates/NOTES.txt": "shared notes",
+		"parent/charts/own/templates/NOTES.txt":     "own notes",
+	}
+
+	tests := []struct {
+		name   string
+		opts   []PartitionOption
+		expect map[string]string
+	}{
+		{
+			name: "default",
+			expect: map[string]string{
+				"parent":                "shared notes",
+				"parent/charts/child":   "shared notes",
+				"parent/charts/another": "shared notes",
+				"parent/charts/own":     "own notes",
+			},
+		},
+		{
+			name:   "deduped",
+			opts:   []PartitionOption{DedupeNotes(true)},
+			expect: map[string]string{"parent": "shared notes", "parent/charts/own": "own notes"},
+		},
+	}
+
+	for _, tt := range tests {
+		_, _, notes, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder, tt.opts...)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if !reflect.DeepEqual(notes, tt.expect) {
+			t.Errorf("%s: expected notes %v, got %v", tt.name, tt.expect, notes)
+		}
+	}
+
+	// Without the parent, the first subchart by path keeps the shared notes
+	delete(files, "parent/templates/NOTES.txt")
+	_, _, notes, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder, DedupeNotes(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := map[string]string{"parent/charts/another": "shared notes", "parent/charts/own": "own notes"}; !reflect.DeepEqual(notes, expect) {
+		t.Errorf("Expected notes %v, got %v", expect, notes)
+	}
+}
+
+func TestPartitionNotesSubchartNamedLikeParent(t *testing.T) {
+	files := map[string]string{
+		"foo/templates/NOTES.txt":            "parent notes",
+		"foo/charts/foo/templates/NOTES.txt": "child notes",
+	}
+
+	_, _, notes, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{"foo": "parent notes", "foo/charts/foo": "child notes"}
+	if !reflect.DeepEqual(notes, expect) {
+		t.Errorf("Expected notes %v, got %v", expect, notes)
+	}
+}
+
+func TestPartitionPathPrefix(t *testing.T) {
+	files := map[string]string{
+		"parent/templates/cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: parent-config\n",
+		"parent/templates/hook.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: parent-hook
+  annotations:
+    "helm.sh/hook": pre-install
+`,
+		"parent/templates/NOTES.txt":                "parent notes",
+		"parent/charts/child/templates/cm.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: child-config\n",
+		"parent/charts/child/templates/hook.yaml":   "apiVersion: v1\nkind: Pod\nmetadata:\n  name: child-hook\n  annotations:\n    \"helm.sh/hook\": pre-install\n",
+		"parent/charts/child/templates/NOTES.txt":   "child notes",
+		"parent/charts/child/templates/broken.yaml": "kind: Pod\nmetadata: [unterminated",
+	}
+
+	hs, manifests, notes, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder, PathPrefix("parent/templates/"))
+	if err != nil {
+		t.Fatalf("Expected the files of the child to be ignored, got %s", err)
+	}
+	if len(hs) != 1 || hs[0].Name != "parent-hook" {
+		t.Errorf("Expected only the parent hook, got %v", hs)
+	}
+	if len(manifests) != 1 || manifests[0].Head.Metadata.Name != "parent-config" {
+		t.Errorf("Expected only the parent manifest, got %v", manifests)
+	}
+	if expect := map[string]string{"parent": "parent notes"}; !reflect.DeepEqual(notes, expect) {
+		t.Errorf("Expected notes %v, got %v", expect, notes)
+	}
+
+	if _, _, _, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder); err == nil {
+		t.Error("Expected the broken child template to fail without a prefix")
+	}
+}
+
+func TestPartitionDefaultDeletePolicy(t *testing.T) {
+	files := map[string]string{
+		"chart/templates/default.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: default-policy
+  annotations:
+    "helm.sh/hook": pre-install
+`,
+		"chart/templates/explicit.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: explicit-policy
+  annotations:
+    "helm.sh/hook": pre-install
+    "helm.sh/hook-delete-policy": hook-succeeded
+`,
+	}
+
+	tests := []struct {
+		name   string
+		opts   []PartitionOption
+		expect map[string][]release.Hook_DeletePolicy
+	}{
+		{
+			name: "disabled",
+			expect: map[string][]release.Hook_DeletePolicy{
+				"default-policy":  nil,
+				"explicit-policy": {release.Hook_SUCCEEDED},
+			},
+		},
+		{
+			name: "enabled",
+			opts: []PartitionOption{DefaultDeletePolicy(true)},
+			expect: map[string][]release.Hook_DeletePolicy{
+				"default-policy":  {release.Hook_BEFORE_HOOK_CREATION},
+				"explicit-policy": {release.Hook_SUCCEEDED},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		hs, _, _, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder, tt.opts...)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		got := map[string][]release.Hook_DeletePolicy{}
+		for _, h := range hs {
+			if len(h.DeletePolicies) == 0 {
+				got[h.Name] = nil
+				continue
+			}
+			got[h.Name] = h.DeletePolicies
+		}
+		if !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("%s: expected delete policies %v, got %v", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestPartitionToBundle(t *testing.T) {
+	files := map[string]string{
+		"parent/templates/NOTES.txt":  "parent notes",
+		"parent/templates/cm.yaml":    "kind: ConfigMap\nmetadata:\n  name: cm",
+		"parent/templates/svc.yaml":   "kind: Service\nmetadata:\n  name: svc",
+		"parent/templates/hook.yaml":  "kind: Job\nmetadata:\n  name: job\n  annotations:\n    helm.sh/hook: pre-install",
+		"parent/templates/_help.tpl":  "partial",
+		"parent/templates/empty.yaml": " ",
+	}
+
+	hooks, generic, notes, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := PartitionToBundle(files, chartutil.NewVersionSet("v1"), InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(bundle.Hooks, hooks) {
+		t.Errorf("Expected hooks %v, got %v", hooks, bundle.Hooks)
+	}
+	if !reflect.DeepEqual(bundle.Manifests, generic) {
+		t.Errorf("Expected manifests %v, got %v", generic, bundle.Manifests)
+	}
+	if !reflect.DeepEqual(bundle.Notes, notes) {
+		t.Errorf("Expected notes %v, got %v", notes, bundle.Notes)
+	}
+
+	files["parent/templates/bad.yaml"] = "kind: ConfigMap\nmetadata: [\n"
+	if _, err := PartitionToBundle(files, chartutil.NewVersionSet("v1"), InstallOrder); err == nil {
+		t.Error("Expected the error of Partition to be returned")
+	}
+}
+
+func TestMergePartitions(t *testing.T) {
+	partition := func(files map[string]string) PartitionBundle {
+		bundle, err := PartitionToBundle(files, chartutil.NewVersionSet("v1"), InstallOrder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *bundle
+	}
+
+	chart := partition(map[string]string{
+		"chart/templates/NOTES.txt": "chart notes",
+		"chart/templates/svc.yaml":  "kind: Service\nmetadata:\n  name: web",
+		"chart/templates/hook.yaml": "kind: Job\nmetadata:\n  name: migrate\n  annotations:\n    helm.sh/hook: pre-install",
+	})
+	extra := partition(map[string]string{
+		"extra/templates/ns.yaml": "kind: Namespace\nmetadata:\n  name: web",
+		"extra/templates/cm.yaml": "kind: ConfigMap\nmetadata:\n  name: web",
+	})
+
+	merged, err := MergePartitions(chart, extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := []string{}
+	for _, m := range merged.Manifests {
+		kinds = append(kinds, m.Head.Kind)
+	}
+	if expect := []string{"Namespace", "ConfigMap", "Service"}; !reflect.DeepEqual(kinds, expect) {
+		t.Errorf("Expected manifests sorted as %v, got %v", expect, kinds)
+	}
+	if len(merged.Hooks) != 1 || merged.Hooks[0].Name != "migrate" {
+		t.Errorf("Expected the hook of the chart, got %v", merged.Hooks)
+	}
+	if expect := map[string]string{"chart": "chart notes"}; !reflect.DeepEqual(merged.Notes, expect) {
+		t.Errorf("Expected notes %v, got %v", expect, merged.Notes)
+	}
+	if chart.Manifests[0].Head.Kind != "Service" || len(extra.Manifests) != 2 {
+		t.Error("Expected the merged bundles not to be modified")
+	}
+
+	overlapping := []struct {
+		name  string
+		files map[string]string
+	}{
+		{"manifest", map[string]string{"extra/templates/svc.yaml": "kind: Service\nmetadata:\n  name: web"}},
+		{"hook", map[string]string{"extra/templates/hook.yaml": "kind: Job\nmetadata:\n  name: migrate\n  annotations:\n    helm.sh/hook: post-install"}},
+		{"notes", map[string]string{"chart/templates/NOTES.txt": "other notes"}},
+	}
+	for _, tt := range overlapping {
+		if _, err := MergePartitions(chart, partition(tt.files)); err == nil {
+			t.Errorf("%s: expected an error for an overlapping %s", tt.name, tt.name)
+		}
+	}
+
+	// The same name in another namespace is another resource
+	other := partition(map[string]string{"extra/templates/svc.yaml": "kind: Service\nmetadata:\n  name: web\n  namespace: other"})
+	if _, err := MergePartitions(chart, other); err != nil {
+		t.Errorf("Expected resources in different namespaces to merge, got %s", err)
+	}
+}
+
+func TestPartitionCollectSkipped(t *testing.T) {
+	files := map[string]string{
+		"chart/templates/_helpers.tpl": "{{ define \"name\" }}name{{ end }}",
+		"chart/templates/empty.yaml":   "\n  \n",
+		"chart/templates/hook.yaml":    "kind: Job\nmetadata:\n  name: job\n  annotations:\n    helm.sh/hook: pre-launch",
+		"chart/templates/cm.yaml":      "kind: ConfigMap\nmetadata:\n  name: cm",
+		"chart/templates/NOTES.txt":    "notes",
+	}
+
+	var skipped []SkippedResource
+	_, generic, _, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder, CollectSkipped(&skipped))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []SkippedResource{
+		{Path: "chart/templates/_helpers.tpl", Reason: SkipPartial},
+		{Path: "chart/templates/empty.yaml", Reason: SkipEmpty},
+		{Path: "chart/templates/hook.yaml", Reason: SkipUnknownHook},
+	}
+	if !reflect.DeepEqual(skipped, expect) {
+		t.Errorf("Expected skipped %v, got %v", expect, skipped)
+	}
+	if len(generic) != 1 {
+		t.Errorf("Expected only the ConfigMap manifest, got %v", generic)
+	}
+}
+
+func TestPartitionDocIndex(t *testing.T) {
+	files := map[string]string{
+		"chart/templates/all.yaml": `kind: ConfigMap
+metadata:
+  name: first
+---
+kind: Job
+metadata:
+  name: hook
+  annotations:
+    helm.sh/hook: pre-install
+---
+kind: ConfigMap
+metadata:
+  name: third
+---
+kind: ConfigMap
+metadata:
+  name: fourth
+`,
+	}
+
+	_, generic, _, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]int{"first": 0, "third": 2, "fourth": 3}
+	if len(generic) != len(expect) {
+		t.Fatalf("Expected %d manifests, got %d", len(expect), len(generic))
+	}
+	for _, m := range generic {
+		if idx, ok := expect[m.Head.Metadata.Name]; !ok || m.DocIndex != idx {
+			t.Errorf("Expected %s to have index %d, got %d", m.Head.Metadata.Name, idx, m.DocIndex)
+		}
+	}
+}
+
+func TestPartitionAnnotations(t *testing.T) {
+	files := map[string]string{
+		"templates/annotated.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: annotated
+  annotations:
+    meta.helm.sh/release-name: test
+`,
+		"templates/plain.yaml": `apiVersion: v1
+kind: Secret
+metadata:
+  name: plain
+`,
+	}
+
+	_, generic, _, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(generic) != 2 {
+		t.Fatalf("Expected 2 manifests, got %d", len(generic))
+	}
+
+	for _, m := range generic {
+		switch m.Head.Metadata.Name {
+		case "annotated":
+			if m.Annotations["meta.helm.sh/release-name"] != "test" {
+				t.Errorf("Expected the release-name annotation, got %v", m.Annotations)
+			}
+		case "plain":
+			if len(m.Annotations) != 0 {
+				t.Errorf("Expected no annotations, got %v", m.Annotations)
+			}
+		default:
+			t.Errorf("Unexpected manifest %q", m.Head.Metadata.Name)
+		}
+	}
+}
+
+func TestPartitionTargetNamespace(t *testing.T) {
+	files := map[string]string{
+		"templates/explicit.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: explicit\n  namespace: other\n",
+		"templates/implicit.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: implicit\n",
+		"templates/cluster.yaml":  "apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRole\nmetadata:\n  name: cluster\n",
+	}
+
+	tests := []struct {
+		name   string
+		opts   []PartitionOption
+		expect map[string]string
+	}{
+		{
+			name:   "without a target namespace",
+			expect: map[string]string{"explicit": "other", "implicit": "", "cluster": ""},
+		},
+		{
+			name:   "with a target namespace",
+			opts:   []PartitionOption{TargetNamespace("target")},
+			expect: map[string]string{"explicit": "other", "implicit": "target", "cluster": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		_, generic, _, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder, tt.opts...)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		got := map[string]string{}
+		for _, m := range generic {
+			got[m.Head.Metadata.Name] = m.Head.Metadata.Namespace
+		}
+		if !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("%s: expected namespaces %v, got %v", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestManifestToUnstructured(t *testing.T) {
+	files := map[string]string{
+		"templates/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+`,
+	}
+	_, generic, _, err := Partition(files, chartutil.NewVersionSet("v1", "apps/v1"), InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(generic) != 1 {
+		t.Fatalf("Expected 1 manifest, got %d", len(generic))
+	}
+
+	obj, err := generic[0].ToUnstructured()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != "apps" || gvk.Version != "v1" || gvk.Kind != "Deployment" {
+		t.Errorf("Unexpected GroupVersionKind %v", gvk)
+	}
+	if obj.GetName() != "web" {
+		t.Errorf("Expected name web, got %q", obj.GetName())
+	}
+
+	bad := Manifest{Name: "templates/bad.yaml", Content: "metadata:\n  name: bad\n", Head: &util.SimpleHead{}}
+	if _, err := bad.ToUnstructured(); err == nil {
+		t.Error("Expected an error for a manifest without a kind")
+	}
+}
+
+func TestHookWeight(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expect      int32
+		wantErr     bool
+	}{
+		{"valid", map[string]string{hooks.HookWeightAnno: "5"}, 5, false},
+		{"negative", map[string]string{hooks.HookWeightAnno: "-3"}, -3, false},
+		{"missing", map[string]string{}, 0, false},
+		{"nil annotations", nil, 0, false},
+		{"malformed", map[string]string{hooks.HookWeightAnno: "heavy"}, 0, true},
+		{"out of range", map[string]string{hooks.HookWeightAnno: "4294967296"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := HookWeight(tt.annotations)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %t, got %v", tt.name, tt.wantErr, err)
+		}
+		if got != tt.expect {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestHookTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expect      int64
+		wantErr     bool
+	}{
+		{"valid", map[string]string{hooks.HookTimeoutAnno: "120"}, 120, false},
+		{"missing", map[string]string{}, 0, false},
+		{"nil annotations", nil, 0, false},
+		{"malformed", map[string]string{hooks.HookTimeoutAnno: "2m"}, 0, true},
+		{"zero", map[string]string{hooks.HookTimeoutAnno: "0"}, 0, true},
+		{"negative", map[string]string{hooks.HookTimeoutAnno: "-30"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := HookTimeout(tt.annotations)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %t, got %v", tt.name, tt.wantErr, err)
+		}
+		if got != tt.expect {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestPartitionHookTimeout(t *testing.T) {
+	hook := func(timeout string) string {
+		return `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    "helm.sh/hook": pre-install
+    "helm.sh/hook-timeout": "` + timeout + `"
+`
+	}
+
+	tests := []struct {
+		name    string
+		timeout string
+		expect  int64
+	}{
+		{"valid", "600", 600},
+		{"invalid", "ten minutes", 0},
+	}
+
+	for _, tt := range tests {
+		hs, _, _, err := Partition(map[string]string{"templates/job.yaml": hook(tt.timeout)}, chartutil.NewVersionSet("v1"), InstallOrder)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if len(hs) != 1 {
+			t.Fatalf("%s: expected 1 hook, got %d", tt.name, len(hs))
+		}
+		if hs[0].Timeout != tt.expect {
+			t.Errorf("%s: expected timeout %d, got %d", tt.name, tt.expect, hs[0].Timeout)
+		}
+	}
+}
+
+func TestHookDeletePolicies(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expect      []release.Hook_DeletePolicy
+	}{
+		{
+			name:        "valid",
+			annotations: map[string]string{hooks.HookDeleteAnno: "hook-succeeded, Hook-Failed"},
+			expect:      []release.Hook_DeletePolicy{release.Hook_SUCCEEDED, release.Hook_FAILED},
+		},
+		{
+			name:        "duplicates",
+			annotations: map[string]string{hooks.HookDeleteAnno: "before-hook-creation,before-hook-creation"},
+			expect:      []release.Hook_DeletePolicy{release.Hook_BEFORE_HOOK_CREATION},
+		},
+		{
+			name:        "missing",
+			annotations: map[string]string{},
+			expect:      []release.Hook_DeletePolicy{},
+		},
+		{
+			name:        "malformed",
+			annotations: map[string]string{hooks.HookDeleteAnno: "sometimes,hook-failed"},
+			expect:      []release.Hook_DeletePolicy{release.Hook_FAILED},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := HookDeletePolicies(tt.annotations); !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestPartitionOutputLogPolicies(t *testing.T) {
+	files := map[string]string{
+		"templates/capture.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: capture
+  annotations:
+    "helm.sh/hook": test-success
+    "helm.sh/hook-output-log-policy": hook-failed
+`,
+		"templates/quiet.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: quiet
+  annotations:
+    "helm.sh/hook": test-success
+`,
+	}
+
+	hs, _, _, err := Partition(files, chartutil.NewVersionSet("v1"), InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string][]release.Hook_OutputLogPolicy{
+		"capture": {release.Hook_OUTPUT_ON_FAILED},
+		"quiet":   {},
+	}
+	if len(hs) != len(expect) {
+		t.Fatalf("Expected %d hooks, got %d", len(expect), len(hs))
+	}
+	for _, h := range hs {
+		if !reflect.DeepEqual(h.OutputLogPolicies, expect[h.Name]) {
+			t.Errorf("%s: expected output log policies %v, got %v", h.Name, expect[h.Name], h.OutputLogPolicies)
+		}
+	}
+}
+
+func TestHookOutputLogPolicies(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expect      []release.Hook_OutputLogPolicy
+	}{
+		{
+			name:        "both",
+			annotations: map[string]string{hooks.HookOutputLogAnno: "hook-succeeded, Hook-Failed"},
+			expect:      []release.Hook_OutputLogPolicy{release.Hook_OUTPUT_ON_SUCCEEDED, release.Hook_OUTPUT_ON_FAILED},
+		},
+		{
+			name:        "missing",
+			annotations: map[string]string{},
+			expect:      []release.Hook_OutputLogPolicy{},
+		},
+		{
+			name:        "malformed",
+			annotations: map[string]string{hooks.HookOutputLogAnno: "always,hook-failed,hook-failed"},
+			expect:      []release.Hook_OutputLogPolicy{release.Hook_OUTPUT_ON_FAILED},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := HookOutputLogPolicies(tt.annotations); !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expect, got)
+		}
+	}
+}
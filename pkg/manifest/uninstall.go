@@ -0,0 +1,44 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// UninstallPlan is the order in which a release is uninstalled: first the
+// pre-delete hooks run, then the resources are deleted, and finally the
+// post-delete hooks run.
+type UninstallPlan struct {
+	PreDelete  []*release.Hook
+	Resources  []Manifest
+	PostDelete []*release.Hook
+}
+
+// PlanUninstall builds the UninstallPlan for the hooks and manifests returned
+// by Partition. Hooks are ordered by weight, as they are executed, and resources
+// by UninstallOrder. The given manifests are not modified.
+func PlanUninstall(hooks []*release.Hook, manifests []Manifest) UninstallPlan {
+	plan := HookPlan(hooks)
+	resources := make([]Manifest, len(manifests))
+	copy(resources, manifests)
+	return UninstallPlan{
+		PreDelete:  plan[release.Hook_PRE_DELETE],
+		Resources:  sortByKind(resources, UninstallOrder),
+		PostDelete: plan[release.Hook_POST_DELETE],
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestPlanUninstall(t *testing.T) {
+	hook := func(name, event, weight string) string {
+		return `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: ` + name + `
+  annotations:
+    helm.sh/hook: ` + event + `
+    helm.sh/hook-weight: "` + weight + `"
+`
+	}
+	files := map[string]string{
+		"chart/templates/backup.yaml":    hook("backup", "pre-delete", "5"),
+		"chart/templates/drain.yaml":     hook("drain", "pre-delete", "-5"),
+		"chart/templates/notify.yaml":    hook("notify", "post-delete", "0"),
+		"chart/templates/install.yaml":   hook("setup", "post-install", "0"),
+		"chart/templates/service.yaml":   "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n",
+		"chart/templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n",
+		"chart/templates/namespace.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: ns\n",
+	}
+
+	hooks, manifests, _, err := Partition(files, chartutil.DefaultVersionSet, InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := PlanUninstall(hooks, manifests)
+
+	hookNames := func(hs []*release.Hook) []string {
+		names := []string{}
+		for _, h := range hs {
+			names = append(names, h.Name)
+		}
+		return names
+	}
+	if got, expect := hookNames(plan.PreDelete), []string{"drain", "backup"}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected pre-delete hooks %v, got %v", expect, got)
+	}
+	if got, expect := hookNames(plan.PostDelete), []string{"notify"}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected post-delete hooks %v, got %v", expect, got)
+	}
+
+	kinds := []string{}
+	for _, m := range plan.Resources {
+		kinds = append(kinds, m.Head.Kind)
+	}
+	if expect := []string{"Service", "ConfigMap", "Namespace"}; !reflect.DeepEqual(kinds, expect) {
+		t.Errorf("Expected resources to be deleted in order %v, got %v", expect, kinds)
+	}
+	if manifests[0].Head.Kind != "Namespace" {
+		t.Errorf("Expected the partitioned manifests to be left in install order, got %s first", manifests[0].Head.Kind)
+	}
+}
@@ -113,6 +113,27 @@ func (x Hook_DeletePolicy) String() string {
 }
 func (Hook_DeletePolicy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0, 1} }
 
+type Hook_OutputLogPolicy int32
+
+const (
+	Hook_OUTPUT_ON_SUCCEEDED Hook_OutputLogPolicy = 0
+	Hook_OUTPUT_ON_FAILED    Hook_OutputLogPolicy = 1
+)
+
+var Hook_OutputLogPolicy_name = map[int32]string{
+	0: "OUTPUT_ON_SUCCEEDED",
+	1: "OUTPUT_ON_FAILED",
+}
+var Hook_OutputLogPolicy_value = map[string]int32{
+	"OUTPUT_ON_SUCCEEDED": 0,
+	"OUTPUT_ON_FAILED":    1,
+}
+
+func (x Hook_OutputLogPolicy) String() string {
+	return proto.EnumName(Hook_OutputLogPolicy_name, int32(x))
+}
+func (Hook_OutputLogPolicy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0, 2} }
+
 // Hook defines a hook object.
 type Hook struct {
 	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
@@ -130,6 +151,10 @@ type Hook struct {
 	Weight int32 `protobuf:"varint,7,opt,name=weight" json:"weight,omitempty"`
 	// DeletePolicies are the policies that indicate when to delete the hook
 	DeletePolicies []Hook_DeletePolicy `protobuf:"varint,8,rep,packed,name=delete_policies,json=deletePolicies,enum=hapi.release.Hook_DeletePolicy" json:"delete_policies,omitempty"`
+	// OutputLogPolicies are the policies that indicate when to capture the logs of the hook
+	OutputLogPolicies []Hook_OutputLogPolicy `protobuf:"varint,9,rep,packed,name=output_log_policies,json=outputLogPolicies,enum=hapi.release.Hook_OutputLogPolicy" json:"output_log_policies,omitempty"`
+	// Timeout is the number of seconds the hook may run, or 0 to use the timeout of the release
+	Timeout int64 `protobuf:"varint,10,opt,name=timeout" json:"timeout,omitempty"`
 }
 
 func (m *Hook) Reset()                    { *m = Hook{} }
@@ -193,43 +218,62 @@ func (m *Hook) GetDeletePolicies() []Hook_DeletePolicy {
 	return nil
 }
 
+func (m *Hook) GetOutputLogPolicies() []Hook_OutputLogPolicy {
+	if m != nil {
+		return m.OutputLogPolicies
+	}
+	return nil
+}
+
+func (m *Hook) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Hook)(nil), "hapi.release.Hook")
 	proto.RegisterEnum("hapi.release.Hook_Event", Hook_Event_name, Hook_Event_value)
 	proto.RegisterEnum("hapi.release.Hook_DeletePolicy", Hook_DeletePolicy_name, Hook_DeletePolicy_value)
+	proto.RegisterEnum("hapi.release.Hook_OutputLogPolicy", Hook_OutputLogPolicy_name, Hook_OutputLogPolicy_value)
 }
 
 func init() { proto.RegisterFile("hapi/release/hook.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 453 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0x51, 0x8f, 0x9a, 0x40,
-	0x10, 0x80, 0x8f, 0x53, 0x41, 0x47, 0xcf, 0xdb, 0x6e, 0x9a, 0x76, 0xe3, 0xcb, 0x19, 0x9f, 0x7c,
-	0xc2, 0xe6, 0x9a, 0xfe, 0x00, 0x84, 0xb9, 0x6a, 0x24, 0x60, 0x16, 0x4c, 0x93, 0xbe, 0x10, 0xae,
-	0xee, 0x29, 0x11, 0x81, 0x08, 0xb6, 0xe9, 0x1f, 0xed, 0x3f, 0xe8, 0xff, 0x68, 0x76, 0x45, 0x7a,
-	0x49, 0xfb, 0x36, 0xf3, 0xcd, 0xb7, 0xb3, 0x33, 0xbb, 0xf0, 0x7e, 0x1f, 0x17, 0xc9, 0xec, 0x24,
-	0x52, 0x11, 0x97, 0x62, 0xb6, 0xcf, 0xf3, 0x83, 0x59, 0x9c, 0xf2, 0x2a, 0xa7, 0x03, 0x59, 0x30,
-	0xeb, 0xc2, 0xe8, 0x61, 0x97, 0xe7, 0xbb, 0x54, 0xcc, 0x54, 0xed, 0xf9, 0xfc, 0x32, 0xab, 0x92,
-	0xa3, 0x28, 0xab, 0xf8, 0x58, 0x5c, 0xf4, 0xc9, 0xaf, 0x36, 0xb4, 0x17, 0x79, 0x7e, 0xa0, 0x14,
-	0xda, 0x59, 0x7c, 0x14, 0x4c, 0x1b, 0x6b, 0xd3, 0x1e, 0x57, 0xb1, 0x64, 0x87, 0x24, 0xdb, 0xb2,
-	0xdb, 0x0b, 0x93, 0xb1, 0x64, 0x45, 0x5c, 0xed, 0x59, 0xeb, 0xc2, 0x64, 0x4c, 0x47, 0xd0, 0x3d,
-	0xc6, 0x59, 0xf2, 0x22, 0xca, 0x8a, 0xb5, 0x15, 0x6f, 0x72, 0xfa, 0x01, 0x74, 0xf1, 0x5d, 0x64,
-	0x55, 0xc9, 0x3a, 0xe3, 0xd6, 0x74, 0xf8, 0xc8, 0xcc, 0xd7, 0x03, 0x9a, 0xf2, 0x6e, 0x13, 0xa5,
-	0xc0, 0x6b, 0x8f, 0x7e, 0x82, 0x6e, 0x1a, 0x97, 0x55, 0x74, 0x3a, 0x67, 0x4c, 0x1f, 0x6b, 0xd3,
-	0xfe, 0xe3, 0xc8, 0xbc, 0xac, 0x61, 0x5e, 0xd7, 0x30, 0xc3, 0xeb, 0x1a, 0xdc, 0x90, 0x2e, 0x3f,
-	0x67, 0xf4, 0x1d, 0xe8, 0x3f, 0x44, 0xb2, 0xdb, 0x57, 0xcc, 0x18, 0x6b, 0xd3, 0x0e, 0xaf, 0x33,
-	0xba, 0x80, 0xfb, 0xad, 0x48, 0x45, 0x25, 0xa2, 0x22, 0x4f, 0x93, 0x6f, 0x89, 0x28, 0x59, 0x57,
-	0x4d, 0xf2, 0xf0, 0x9f, 0x49, 0x1c, 0x65, 0xae, 0xa5, 0xf8, 0x93, 0x0f, 0xb7, 0x7f, 0xb3, 0x44,
-	0x94, 0x93, 0xdf, 0x1a, 0x74, 0xd4, 0xa8, 0xb4, 0x0f, 0xc6, 0xc6, 0x5b, 0x79, 0xfe, 0x17, 0x8f,
-	0xdc, 0xd0, 0x7b, 0xe8, 0xaf, 0x39, 0x46, 0x4b, 0x2f, 0x08, 0x2d, 0xd7, 0x25, 0x1a, 0x25, 0x30,
-	0x58, 0xfb, 0x41, 0xd8, 0x90, 0x5b, 0x3a, 0x04, 0x90, 0x8a, 0x83, 0x2e, 0x86, 0x48, 0x5a, 0xea,
-	0x88, 0x34, 0x6a, 0xd0, 0xbe, 0xf6, 0xd8, 0xac, 0x3f, 0x73, 0xcb, 0x41, 0xd2, 0x69, 0x7a, 0x5c,
-	0x89, 0xae, 0x08, 0xc7, 0x88, 0xfb, 0xae, 0x3b, 0xb7, 0xec, 0x15, 0x31, 0xe8, 0x1b, 0xb8, 0x53,
-	0x4e, 0x83, 0xba, 0x94, 0xc1, 0x5b, 0x8e, 0x2e, 0x5a, 0x01, 0x46, 0x21, 0x06, 0x61, 0x14, 0x6c,
-	0x6c, 0x1b, 0x83, 0x80, 0xf4, 0xfe, 0xa9, 0x3c, 0x59, 0x4b, 0x77, 0xc3, 0x91, 0x80, 0xbc, 0xdb,
-	0xe6, 0x4e, 0x33, 0x6d, 0x7f, 0x62, 0xc3, 0xe0, 0xf5, 0x3b, 0xd0, 0x3b, 0xe8, 0xa9, 0x3e, 0xe8,
-	0xa0, 0x43, 0x6e, 0x28, 0x80, 0x2e, 0x0f, 0xa3, 0x43, 0x34, 0xd9, 0x75, 0x8e, 0x4f, 0x3e, 0xc7,
-	0x68, 0xe1, 0xfb, 0xab, 0xc8, 0xe6, 0x68, 0x85, 0x4b, 0xdf, 0x23, 0xb7, 0xf3, 0xde, 0x57, 0xa3,
-	0x7e, 0xd9, 0x67, 0x5d, 0x7d, 0xdb, 0xc7, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x3b, 0xcf, 0xed,
-	0xd9, 0xb4, 0x02, 0x00, 0x00,
+	// 514 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0x6d, 0x93, 0x4d, 0x6f, 0xda, 0x40,
+	0x10, 0x86, 0xcb, 0x97, 0x0d, 0x03, 0x01, 0x67, 0x13, 0x35, 0x2b, 0x2e, 0x89, 0x38, 0xe5, 0x64,
+	0xa2, 0x54, 0xbd, 0xd7, 0xd8, 0x9b, 0x82, 0xb0, 0x6c, 0xb4, 0xb6, 0x55, 0xa9, 0x17, 0xcb, 0x29,
+	0x1b, 0xb0, 0x02, 0x5e, 0x0b, 0x4c, 0xab, 0xfe, 0x81, 0xfe, 0xd3, 0xfe, 0x8f, 0xee, 0x2e, 0xe6,
+	0xa3, 0x6d, 0x6e, 0x33, 0xef, 0xbc, 0xf3, 0xec, 0xcc, 0xae, 0x0d, 0x37, 0xcb, 0x24, 0x4f, 0x87,
+	0x1b, 0xb6, 0x62, 0xc9, 0x96, 0x0d, 0x97, 0x9c, 0xbf, 0x9a, 0xf9, 0x86, 0x17, 0x1c, 0x75, 0x64,
+	0xc1, 0x2c, 0x0b, 0xfd, 0xdb, 0x05, 0xe7, 0x8b, 0x15, 0x1b, 0xaa, 0xda, 0xf3, 0xee, 0x65, 0x58,
+	0xa4, 0x6b, 0xb6, 0x2d, 0x92, 0x75, 0xbe, 0xb7, 0x0f, 0x7e, 0x69, 0x50, 0x1f, 0x8b, 0x6e, 0x84,
+	0xa0, 0x9e, 0x25, 0x6b, 0x86, 0x2b, 0x77, 0x95, 0xfb, 0x16, 0x55, 0xb1, 0xd4, 0x5e, 0xd3, 0x6c,
+	0x8e, 0xab, 0x7b, 0x4d, 0xc6, 0x52, 0xcb, 0x93, 0x62, 0x89, 0x6b, 0x7b, 0x4d, 0xc6, 0xa8, 0x0f,
+	0xcd, 0x75, 0x92, 0xa5, 0x2f, 0x82, 0x8c, 0xeb, 0x4a, 0x3f, 0xe6, 0xe8, 0x01, 0x34, 0xf6, 0x9d,
+	0x65, 0xc5, 0x16, 0x37, 0xee, 0x6a, 0xf7, 0xdd, 0x47, 0x6c, 0x9e, 0x0f, 0x68, 0xca, 0xb3, 0x4d,
+	0x22, 0x0d, 0xb4, 0xf4, 0xa1, 0x8f, 0xd0, 0x5c, 0x25, 0xdb, 0x22, 0xde, 0xec, 0x32, 0xac, 0x09,
+	0x5a, 0xfb, 0xb1, 0x6f, 0xee, 0xd7, 0x30, 0x0f, 0x6b, 0x98, 0xe1, 0x61, 0x0d, 0xaa, 0x4b, 0x2f,
+	0xdd, 0x65, 0xe8, 0x3d, 0x68, 0x3f, 0x58, 0xba, 0x58, 0x16, 0x58, 0x17, 0x4d, 0x0d, 0x5a, 0x66,
+	0x68, 0x0c, 0xbd, 0xb9, 0x38, 0xac, 0x60, 0x71, 0xce, 0x57, 0xe9, 0xb7, 0x94, 0x6d, 0x71, 0x53,
+	0x4d, 0x72, 0xfb, 0xc6, 0x24, 0x8e, 0x72, 0xce, 0xa4, 0xf1, 0x27, 0xed, 0xce, 0x4f, 0x99, 0x68,
+	0x43, 0x14, 0xae, 0xf8, 0xae, 0xc8, 0x77, 0x45, 0xbc, 0xe2, 0x8b, 0x13, 0xad, 0xa5, 0x68, 0x83,
+	0x37, 0x68, 0xbe, 0x72, 0xbb, 0x7c, 0x51, 0x02, 0x2f, 0xf9, 0x5f, 0x82, 0x68, 0x1e, 0xfc, 0xae,
+	0x40, 0x43, 0xad, 0x8f, 0xda, 0xa0, 0x47, 0xde, 0xd4, 0xf3, 0xbf, 0x78, 0xc6, 0x3b, 0xd4, 0x83,
+	0xf6, 0x8c, 0x92, 0x78, 0xe2, 0x05, 0xa1, 0xe5, 0xba, 0x46, 0x05, 0x19, 0xd0, 0x99, 0xf9, 0x41,
+	0x78, 0x54, 0xaa, 0xa8, 0x0b, 0x20, 0x2d, 0x0e, 0x71, 0x49, 0x48, 0x8c, 0x9a, 0x6a, 0x91, 0x8e,
+	0x52, 0xa8, 0x1f, 0x18, 0xd1, 0xec, 0x33, 0xb5, 0x1c, 0x62, 0x34, 0x8e, 0x8c, 0x83, 0xa2, 0x29,
+	0x45, 0x58, 0xa8, 0xef, 0xba, 0x23, 0xcb, 0x9e, 0x1a, 0x3a, 0xba, 0x84, 0x0b, 0xe5, 0x39, 0x4a,
+	0x4d, 0x84, 0xe1, 0x9a, 0x0a, 0xa6, 0x15, 0x90, 0x38, 0x24, 0xa2, 0x14, 0x44, 0xb6, 0x4d, 0x82,
+	0xc0, 0x68, 0xfd, 0x57, 0x79, 0xb2, 0x26, 0x6e, 0x44, 0x89, 0x01, 0xf2, 0x6c, 0x9b, 0x3a, 0xc7,
+	0x69, 0xdb, 0x03, 0x1b, 0x3a, 0xe7, 0x77, 0x8b, 0x2e, 0xa0, 0xa5, 0x38, 0xc4, 0x21, 0x8e, 0xd8,
+	0x17, 0x40, 0x93, 0xcd, 0x22, 0xae, 0x48, 0xea, 0x88, 0x3c, 0xf9, 0x62, 0xae, 0xb1, 0xef, 0x4f,
+	0x63, 0x9b, 0x12, 0x2b, 0x9c, 0xf8, 0x9e, 0x51, 0x1d, 0x7c, 0x82, 0xde, 0x3f, 0x57, 0x8a, 0x6e,
+	0xe0, 0xca, 0x8f, 0xc2, 0x59, 0x14, 0xc6, 0xbe, 0x17, 0x9f, 0x13, 0xaf, 0xc1, 0x38, 0x15, 0xce,
+	0xd8, 0xba, 0xfc, 0x03, 0xc4, 0x3b, 0x60, 0x10, 0x5f, 0x49, 0x8d, 0x1e, 0xd2, 0x51, 0xeb, 0xab,
+	0x5e, 0xbe, 0xdd, 0xb3, 0xa6, 0x3e, 0xb3, 0x0f, 0x7f, 0x00, 0x35, 0x74, 0xdd, 0xfc, 0x64, 0x03,
+	0x00, 0x00,
 }
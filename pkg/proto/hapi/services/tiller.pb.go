@@ -131,6 +131,10 @@ type ListReleasesRequest struct {
 	StatusCodes []hapi_release3.Status_Code `protobuf:"varint,6,rep,packed,name=status_codes,json=statusCodes,enum=hapi.release.Status_Code" json:"status_codes,omitempty"`
 	// Namespace is the filter to select releases only from a specific namespace.
 	Namespace string `protobuf:"bytes,7,opt,name=namespace" json:"namespace,omitempty"`
+	// Chart is the filter to select only releases of the chart with this name.
+	Chart string `protobuf:"bytes,8,opt,name=chart" json:"chart,omitempty"`
+	// IncludeDeleted lists DELETED releases as well, whatever the status codes.
+	IncludeDeleted bool `protobuf:"varint,9,opt,name=include_deleted,json=includeDeleted" json:"include_deleted,omitempty"`
 }
 
 func (m *ListReleasesRequest) Reset()                    { *m = ListReleasesRequest{} }
@@ -187,6 +191,20 @@ func (m *ListReleasesRequest) GetNamespace() string {
 	return ""
 }
 
+func (m *ListReleasesRequest) GetChart() string {
+	if m != nil {
+		return m.Chart
+	}
+	return ""
+}
+
+func (m *ListReleasesRequest) GetIncludeDeleted() bool {
+	if m != nil {
+		return m.IncludeDeleted
+	}
+	return false
+}
+
 // ListSort defines sorting fields on a release list.
 type ListSort struct {
 }
@@ -378,6 +396,8 @@ type UpdateReleaseRequest struct {
 	Force bool `protobuf:"varint,11,opt,name=force" json:"force,omitempty"`
 	// Description, if set, will set the description for the updated release
 	Description string `protobuf:"bytes,12,opt,name=description" json:"description,omitempty"`
+	// WaitForJobs, if true and wait is set, will also wait until all Jobs have completed
+	WaitForJobs bool `protobuf:"varint,13,opt,name=wait_for_jobs,json=waitForJobs" json:"wait_for_jobs,omitempty"`
 }
 
 func (m *UpdateReleaseRequest) Reset()                    { *m = UpdateReleaseRequest{} }
@@ -469,6 +489,13 @@ func (m *UpdateReleaseRequest) GetDescription() string {
 	return ""
 }
 
+func (m *UpdateReleaseRequest) GetWaitForJobs() bool {
+	if m != nil {
+		return m.WaitForJobs
+	}
+	return false
+}
+
 // UpdateReleaseResponse is the response to an update request.
 type UpdateReleaseResponse struct {
 	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
@@ -506,6 +533,10 @@ type RollbackReleaseRequest struct {
 	Force bool `protobuf:"varint,8,opt,name=force" json:"force,omitempty"`
 	// Description, if set, will set the description for the rollback
 	Description string `protobuf:"bytes,9,opt,name=description" json:"description,omitempty"`
+	// CleanupOnFail, if true, deletes the resources created by a rollback that fails
+	CleanupOnFail bool `protobuf:"varint,10,opt,name=cleanup_on_fail,json=cleanupOnFail" json:"cleanup_on_fail,omitempty"`
+	// WaitForJobs, if true and wait is set, will also wait until all Jobs have completed
+	WaitForJobs bool `protobuf:"varint,11,opt,name=wait_for_jobs,json=waitForJobs" json:"wait_for_jobs,omitempty"`
 }
 
 func (m *RollbackReleaseRequest) Reset()                    { *m = RollbackReleaseRequest{} }
@@ -576,6 +607,20 @@ func (m *RollbackReleaseRequest) GetDescription() string {
 	return ""
 }
 
+func (m *RollbackReleaseRequest) GetCleanupOnFail() bool {
+	if m != nil {
+		return m.CleanupOnFail
+	}
+	return false
+}
+
+func (m *RollbackReleaseRequest) GetWaitForJobs() bool {
+	if m != nil {
+		return m.WaitForJobs
+	}
+	return false
+}
+
 // RollbackReleaseResponse is the response to an update request.
 type RollbackReleaseResponse struct {
 	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
@@ -621,6 +666,8 @@ type InstallReleaseRequest struct {
 	DisableCrdHook bool `protobuf:"varint,10,opt,name=disable_crd_hook,json=disableCrdHook" json:"disable_crd_hook,omitempty"`
 	// Description, if set, will set the description for the installed release
 	Description string `protobuf:"bytes,11,opt,name=description" json:"description,omitempty"`
+	// WaitForJobs, if true and wait is set, will also wait until all Jobs have completed
+	WaitForJobs bool `protobuf:"varint,12,opt,name=wait_for_jobs,json=waitForJobs" json:"wait_for_jobs,omitempty"`
 }
 
 func (m *InstallReleaseRequest) Reset()                    { *m = InstallReleaseRequest{} }
@@ -705,6 +752,13 @@ func (m *InstallReleaseRequest) GetDescription() string {
 	return ""
 }
 
+func (m *InstallReleaseRequest) GetWaitForJobs() bool {
+	if m != nil {
+		return m.WaitForJobs
+	}
+	return false
+}
+
 // InstallReleaseResponse is the response from a release installation.
 type InstallReleaseResponse struct {
 	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
@@ -1412,85 +1466,89 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("hapi/services/tiller.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1267 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0xed, 0x6e, 0xe3, 0x44,
-	0x17, 0x6e, 0xe2, 0x7c, 0x9e, 0x74, 0xf3, 0x66, 0x67, 0xb3, 0xad, 0xeb, 0x77, 0x41, 0xc1, 0x08,
-	0x36, 0xbb, 0xb0, 0x29, 0x04, 0xfe, 0x20, 0x21, 0xa4, 0x6e, 0x36, 0x6a, 0x0b, 0xa5, 0x2b, 0x39,
-	0xdb, 0x45, 0x42, 0x40, 0xe4, 0x26, 0x93, 0xd6, 0xac, 0x63, 0x07, 0xcf, 0xb8, 0x6c, 0x2f, 0x00,
-	0x24, 0xee, 0x83, 0x0b, 0xe1, 0x3e, 0xb8, 0x0e, 0xfe, 0x23, 0xcf, 0x87, 0xeb, 0x71, 0xec, 0xd4,
-	0xf4, 0x4f, 0xe3, 0x99, 0x73, 0xe6, 0x7c, 0x3c, 0xcf, 0x9c, 0x33, 0xa7, 0x60, 0x5c, 0xda, 0x2b,
-	0x67, 0x9f, 0xe0, 0xe0, 0xca, 0x99, 0x61, 0xb2, 0x4f, 0x1d, 0xd7, 0xc5, 0xc1, 0x60, 0x15, 0xf8,
-	0xd4, 0x47, 0xdd, 0x48, 0x36, 0x90, 0xb2, 0x01, 0x97, 0x19, 0x3b, 0xec, 0xc4, 0xec, 0xd2, 0x0e,
-	0x28, 0xff, 0xcb, 0xb5, 0x8d, 0xdd, 0xe4, 0xbe, 0xef, 0x2d, 0x9c, 0x0b, 0x21, 0xe0, 0x2e, 0x02,
-	0xec, 0x62, 0x9b, 0x60, 0xf9, 0xab, 0x1c, 0x92, 0x32, 0xc7, 0x5b, 0xf8, 0x42, 0xf0, 0x7f, 0x45,
-	0x40, 0x31, 0xa1, 0xd3, 0x20, 0xf4, 0x84, 0x70, 0x4f, 0x11, 0x12, 0x6a, 0xd3, 0x90, 0x28, 0xce,
-	0xae, 0x70, 0x40, 0x1c, 0xdf, 0x93, 0xbf, 0x5c, 0x66, 0xfe, 0x55, 0x86, 0x07, 0x27, 0x0e, 0xa1,
-	0x16, 0x3f, 0x48, 0x2c, 0xfc, 0x4b, 0x88, 0x09, 0x45, 0x5d, 0xa8, 0xba, 0xce, 0xd2, 0xa1, 0x7a,
-	0xa9, 0x57, 0xea, 0x6b, 0x16, 0x5f, 0xa0, 0x1d, 0xa8, 0xf9, 0x8b, 0x05, 0xc1, 0x54, 0x2f, 0xf7,
-	0x4a, 0xfd, 0xa6, 0x25, 0x56, 0xe8, 0x2b, 0xa8, 0x13, 0x3f, 0xa0, 0xd3, 0xf3, 0x6b, 0x5d, 0xeb,
-	0x95, 0xfa, 0xed, 0xe1, 0x07, 0x83, 0x2c, 0x9c, 0x06, 0x91, 0xa7, 0x89, 0x1f, 0xd0, 0x41, 0xf4,
-	0xe7, 0xf9, 0xb5, 0x55, 0x23, 0xec, 0x37, 0xb2, 0xbb, 0x70, 0x5c, 0x8a, 0x03, 0xbd, 0xc2, 0xed,
-	0xf2, 0x15, 0x3a, 0x04, 0x60, 0x76, 0xfd, 0x60, 0x8e, 0x03, 0xbd, 0xca, 0x4c, 0xf7, 0x0b, 0x98,
-	0x7e, 0x19, 0xe9, 0x5b, 0x4d, 0x22, 0x3f, 0xd1, 0x97, 0xb0, 0xcd, 0x21, 0x99, 0xce, 0xfc, 0x39,
-	0x26, 0x7a, 0xad, 0xa7, 0xf5, 0xdb, 0xc3, 0x3d, 0x6e, 0x4a, 0xc2, 0x3f, 0xe1, 0xa0, 0x8d, 0xfc,
-	0x39, 0xb6, 0x5a, 0x5c, 0x3d, 0xfa, 0x26, 0xe8, 0x11, 0x34, 0x3d, 0x7b, 0x89, 0xc9, 0xca, 0x9e,
-	0x61, 0xbd, 0xce, 0x22, 0xbc, 0xd9, 0x30, 0x7f, 0x82, 0x86, 0x74, 0x6e, 0x0e, 0xa1, 0xc6, 0x53,
-	0x43, 0x2d, 0xa8, 0x9f, 0x9d, 0x7e, 0x73, 0xfa, 0xf2, 0xbb, 0xd3, 0xce, 0x16, 0x6a, 0x40, 0xe5,
-	0xf4, 0xe0, 0xdb, 0x71, 0xa7, 0x84, 0xee, 0xc3, 0xbd, 0x93, 0x83, 0xc9, 0xab, 0xa9, 0x35, 0x3e,
-	0x19, 0x1f, 0x4c, 0xc6, 0x2f, 0x3a, 0x65, 0xf3, 0x5d, 0x68, 0xc6, 0x31, 0xa3, 0x3a, 0x68, 0x07,
-	0x93, 0x11, 0x3f, 0xf2, 0x62, 0x3c, 0x19, 0x75, 0x4a, 0xe6, 0x1f, 0x25, 0xe8, 0xaa, 0x14, 0x91,
-	0x95, 0xef, 0x11, 0x1c, 0x71, 0x34, 0xf3, 0x43, 0x2f, 0xe6, 0x88, 0x2d, 0x10, 0x82, 0x8a, 0x87,
-	0xdf, 0x4a, 0x86, 0xd8, 0x77, 0xa4, 0x49, 0x7d, 0x6a, 0xbb, 0x8c, 0x1d, 0xcd, 0xe2, 0x0b, 0xf4,
-	0x29, 0x34, 0x44, 0xea, 0x44, 0xaf, 0xf4, 0xb4, 0x7e, 0x6b, 0xf8, 0x50, 0x05, 0x44, 0x78, 0xb4,
-	0x62, 0x35, 0xf3, 0x10, 0x76, 0x0f, 0xb1, 0x8c, 0x84, 0xe3, 0x25, 0x6f, 0x4c, 0xe4, 0xd7, 0x5e,
-	0x62, 0x16, 0x4c, 0xe4, 0xd7, 0x5e, 0x62, 0xa4, 0x43, 0x5d, 0x5c, 0x37, 0x16, 0x4e, 0xd5, 0x92,
-	0x4b, 0x93, 0x82, 0xbe, 0x6e, 0x48, 0xe4, 0x95, 0x65, 0xe9, 0x43, 0xa8, 0x44, 0x95, 0xc0, 0xcc,
-	0xb4, 0x86, 0x48, 0x8d, 0xf3, 0xd8, 0x5b, 0xf8, 0x16, 0x93, 0xab, 0x54, 0x69, 0x69, 0xaa, 0x8e,
-	0x92, 0x5e, 0x47, 0xbe, 0x47, 0xb1, 0x47, 0xef, 0x16, 0xff, 0x09, 0xec, 0x65, 0x58, 0x12, 0x09,
-	0xec, 0x43, 0x5d, 0x84, 0xc6, 0xac, 0xe5, 0xe2, 0x2a, 0xb5, 0xcc, 0xdf, 0x34, 0xe8, 0x9e, 0xad,
-	0xe6, 0x36, 0xc5, 0x52, 0xb4, 0x21, 0xa8, 0xc7, 0x50, 0x65, 0x1d, 0x45, 0x60, 0x71, 0x9f, 0xdb,
-	0xe6, 0x6d, 0x67, 0x14, 0xfd, 0xb5, 0xb8, 0x1c, 0x3d, 0x85, 0xda, 0x95, 0xed, 0x86, 0x98, 0x30,
-	0x20, 0x62, 0xd4, 0x84, 0x26, 0x6b, 0x47, 0x96, 0xd0, 0x40, 0xbb, 0x50, 0x9f, 0x07, 0xd7, 0x51,
-	0x3f, 0x61, 0x25, 0xd8, 0xb0, 0x6a, 0xf3, 0xe0, 0xda, 0x0a, 0x3d, 0xf4, 0x3e, 0xdc, 0x9b, 0x3b,
-	0xc4, 0x3e, 0x77, 0xf1, 0xf4, 0xd2, 0xf7, 0xdf, 0x10, 0x56, 0x85, 0x0d, 0x6b, 0x5b, 0x6c, 0x1e,
-	0x45, 0x7b, 0xc8, 0x88, 0x6e, 0xd2, 0x2c, 0xc0, 0x36, 0xc5, 0x7a, 0x8d, 0xc9, 0xe3, 0x75, 0x84,
-	0x21, 0x75, 0x96, 0xd8, 0x0f, 0x29, 0x2b, 0x1d, 0xcd, 0x92, 0x4b, 0xf4, 0x1e, 0x6c, 0x07, 0x98,
-	0x60, 0x3a, 0x15, 0x51, 0x36, 0xd8, 0xc9, 0x16, 0xdb, 0x7b, 0xcd, 0xc3, 0x42, 0x50, 0xf9, 0xd5,
-	0x76, 0xa8, 0xde, 0x64, 0x22, 0xf6, 0xcd, 0x8f, 0x85, 0x04, 0xcb, 0x63, 0x20, 0x8f, 0x85, 0x04,
-	0x8b, 0x63, 0x5d, 0xa8, 0x2e, 0xfc, 0x60, 0x86, 0xf5, 0x16, 0x93, 0xf1, 0x05, 0xea, 0x41, 0x6b,
-	0x8e, 0xc9, 0x2c, 0x70, 0x56, 0x34, 0x62, 0x74, 0x9b, 0x61, 0x9a, 0xdc, 0x32, 0x8f, 0xe0, 0x61,
-	0x8a, 0x86, 0xbb, 0x32, 0xfa, 0x7b, 0x19, 0x76, 0x2c, 0xdf, 0x75, 0xcf, 0xed, 0xd9, 0x9b, 0x02,
-	0x9c, 0x26, 0xe0, 0x2f, 0x6f, 0x86, 0x5f, 0xcb, 0x80, 0x3f, 0x71, 0x4d, 0x2b, 0xca, 0x35, 0x55,
-	0x88, 0xa9, 0xe6, 0x13, 0x53, 0x53, 0x89, 0x91, 0xa8, 0xd7, 0x13, 0xa8, 0xc7, 0x90, 0x36, 0x36,
-	0x40, 0xda, 0x5c, 0x87, 0xf4, 0x6b, 0xd8, 0x5d, 0xc3, 0xe1, 0xae, 0xa0, 0xfe, 0x53, 0x86, 0x87,
-	0xc7, 0x1e, 0xa1, 0xb6, 0xeb, 0xa6, 0x30, 0x8d, 0x6b, 0xa2, 0x54, 0xb8, 0x26, 0xca, 0xff, 0xa5,
-	0x26, 0x34, 0x85, 0x14, 0xc9, 0x60, 0x25, 0xc1, 0x60, 0xa1, 0x3a, 0x51, 0xba, 0x53, 0x2d, 0xd5,
-	0x9d, 0xd0, 0x3b, 0x00, 0xfc, 0x62, 0x33, 0xe3, 0x1c, 0xfc, 0x26, 0xdb, 0x39, 0x15, 0xcd, 0x48,
-	0xf2, 0xd5, 0xc8, 0xe6, 0x2b, 0x59, 0x25, 0x7d, 0xe8, 0xc8, 0x78, 0x66, 0xc1, 0x9c, 0xc5, 0x24,
-	0x2a, 0xa5, 0x2d, 0xf6, 0x47, 0xc1, 0x3c, 0x8a, 0x2a, 0xcd, 0x61, 0x6b, 0x9d, 0xc3, 0x63, 0xd8,
-	0x49, 0xc3, 0x7e, 0x57, 0x0a, 0xff, 0x2c, 0xc1, 0xee, 0x99, 0xe7, 0x64, 0x92, 0x98, 0x55, 0x18,
-	0x6b, 0xb0, 0x96, 0x33, 0x60, 0xed, 0x42, 0x75, 0x15, 0x06, 0x17, 0x58, 0xd0, 0xc4, 0x17, 0x49,
-	0xbc, 0x2a, 0x2a, 0x5e, 0xa9, 0x8c, 0xab, 0xeb, 0x19, 0x4f, 0x41, 0x5f, 0x8f, 0xf2, 0x8e, 0x39,
-	0x47, 0x79, 0xc5, 0x6f, 0x57, 0x93, 0xbf, 0x53, 0xe6, 0x03, 0xb8, 0x7f, 0x88, 0xe9, 0x6b, 0x5e,
-	0xa6, 0x02, 0x00, 0x73, 0x0c, 0x28, 0xb9, 0x79, 0xe3, 0x4f, 0x6c, 0xa9, 0xfe, 0xe4, 0x20, 0x27,
-	0xf5, 0xa5, 0x96, 0xf9, 0x05, 0xb3, 0x7d, 0xe4, 0x10, 0xea, 0x07, 0xd7, 0x9b, 0xc0, 0xed, 0x80,
-	0xb6, 0xb4, 0xdf, 0x8a, 0xa7, 0x2d, 0xfa, 0x34, 0x0f, 0x59, 0x04, 0xf1, 0x51, 0x11, 0x41, 0x72,
-	0x50, 0x28, 0x15, 0x1b, 0x14, 0x7e, 0x00, 0xf4, 0x0a, 0xc7, 0x33, 0xcb, 0x2d, 0x6f, 0xac, 0xa4,
-	0xa9, 0xac, 0xd2, 0xa4, 0x43, 0x7d, 0xe6, 0x62, 0xdb, 0x0b, 0x57, 0x82, 0x58, 0xb9, 0x34, 0x7f,
-	0x84, 0x07, 0x8a, 0x75, 0x11, 0x67, 0x94, 0x0f, 0xb9, 0x10, 0xd6, 0xa3, 0x4f, 0xf4, 0x39, 0xd4,
-	0xf8, 0x20, 0xc7, 0x6c, 0xb7, 0x87, 0x8f, 0xd4, 0xb8, 0x99, 0x91, 0xd0, 0x13, 0x93, 0x9f, 0x25,
-	0x74, 0x87, 0x7f, 0x37, 0xa0, 0x2d, 0x47, 0x13, 0x3e, 0x66, 0x22, 0x07, 0xb6, 0x93, 0x33, 0x18,
-	0x7a, 0x92, 0x3f, 0x85, 0xa6, 0x46, 0x69, 0xe3, 0x69, 0x11, 0x55, 0x9e, 0x81, 0xb9, 0xf5, 0x49,
-	0x09, 0x11, 0xe8, 0xa4, 0x47, 0x23, 0xf4, 0x2c, 0xdb, 0x46, 0xce, 0x2c, 0x66, 0x0c, 0x8a, 0xaa,
-	0x4b, 0xb7, 0xe8, 0x8a, 0xdd, 0x19, 0x75, 0x9e, 0x41, 0xb7, 0x9a, 0x51, 0x47, 0x28, 0x63, 0xbf,
-	0xb0, 0x7e, 0xec, 0xf7, 0x67, 0xb8, 0xa7, 0xbc, 0xb8, 0x28, 0x07, 0xad, 0xac, 0xe9, 0xc8, 0xf8,
-	0xa8, 0x90, 0x6e, 0xec, 0x6b, 0x09, 0x6d, 0xb5, 0x8d, 0xa1, 0x1c, 0x03, 0x99, 0x6f, 0x8c, 0xf1,
-	0x71, 0x31, 0xe5, 0xd8, 0x1d, 0x81, 0x4e, 0xba, 0x87, 0xe4, 0xf1, 0x98, 0xd3, 0x11, 0xf3, 0x78,
-	0xcc, 0x6b, 0x4d, 0xe6, 0x16, 0xb2, 0x01, 0x6e, 0x5a, 0x08, 0x7a, 0x9c, 0x4b, 0x88, 0xda, 0x79,
-	0x8c, 0xfe, 0xed, 0x8a, 0xb1, 0x8b, 0x15, 0xfc, 0x2f, 0xf5, 0xa2, 0xa3, 0x1c, 0x68, 0xb2, 0x07,
-	0x20, 0xe3, 0x59, 0x41, 0xed, 0x54, 0x52, 0xa2, 0x2b, 0x6d, 0x48, 0x4a, 0x6d, 0x79, 0x1b, 0x92,
-	0x4a, 0x35, 0x38, 0x73, 0x0b, 0x39, 0xd0, 0xb6, 0x42, 0x4f, 0xb8, 0x8e, 0xda, 0x02, 0xca, 0x39,
-	0xbd, 0xde, 0xd5, 0x8c, 0x27, 0x05, 0x34, 0x6f, 0xea, 0xfb, 0x39, 0x7c, 0xdf, 0x90, 0xaa, 0xe7,
-	0x35, 0xf6, 0x5f, 0xf8, 0x67, 0xff, 0x06, 0x00, 0x00, 0xff, 0xff, 0x9d, 0x73, 0x4f, 0x4d, 0x73,
-	0x10, 0x00, 0x00,
+	// 1339 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0x9d, 0x58, 0x5f, 0x73, 0xdb, 0x44,
+	0x10, 0xaf, 0x2d, 0xc7, 0x96, 0xd7, 0x7f, 0xe2, 0x5c, 0xfe, 0x29, 0xa6, 0x30, 0x45, 0x0c, 0x4d,
+	0x5a, 0xa8, 0x03, 0x86, 0x17, 0x66, 0x18, 0x66, 0xd2, 0xc4, 0x4d, 0x52, 0x42, 0x32, 0xa3, 0x34,
+	0x65, 0x86, 0x01, 0x3c, 0x8a, 0x7d, 0x4e, 0xd4, 0x2a, 0x92, 0xd1, 0xc9, 0xa1, 0xf9, 0x08, 0x7c,
+	0x04, 0xde, 0x79, 0xe1, 0xeb, 0xf0, 0xca, 0x87, 0x81, 0xfb, 0xab, 0x48, 0xb2, 0xe5, 0xa8, 0x79,
+	0xb1, 0xef, 0x76, 0xf7, 0x76, 0xf7, 0xf6, 0xb7, 0xbb, 0xb7, 0x36, 0xb4, 0x2f, 0xed, 0xb1, 0xb3,
+	0x4d, 0x70, 0x70, 0xed, 0x0c, 0x30, 0xd9, 0x0e, 0x1d, 0xd7, 0xc5, 0x41, 0x67, 0x1c, 0xf8, 0xa1,
+	0x8f, 0x56, 0x18, 0xaf, 0xa3, 0x78, 0x1d, 0xc1, 0x6b, 0xaf, 0xf1, 0x13, 0x83, 0x4b, 0x3b, 0x08,
+	0xc5, 0xa7, 0x90, 0x6e, 0xaf, 0xc7, 0xe9, 0xbe, 0x37, 0x72, 0x2e, 0x24, 0x43, 0x98, 0x08, 0xb0,
+	0x8b, 0x6d, 0x82, 0xd5, 0x77, 0xe2, 0x90, 0xe2, 0x39, 0xde, 0xc8, 0x97, 0x8c, 0x0f, 0x12, 0x8c,
+	0x10, 0x93, 0xb0, 0x1f, 0x4c, 0x3c, 0xc9, 0xdc, 0x48, 0x30, 0x49, 0x68, 0x87, 0x13, 0x92, 0x30,
+	0x76, 0x8d, 0x03, 0xe2, 0xf8, 0x9e, 0xfa, 0x16, 0x3c, 0xf3, 0xbf, 0x22, 0x2c, 0x1f, 0x39, 0x24,
+	0xb4, 0xc4, 0x41, 0x62, 0xe1, 0xdf, 0x26, 0x54, 0x31, 0x5a, 0x81, 0x05, 0xd7, 0xb9, 0x72, 0x42,
+	0xa3, 0xf0, 0xa8, 0xb0, 0xa5, 0x59, 0x62, 0x83, 0xd6, 0xa0, 0xec, 0x8f, 0x46, 0x04, 0x87, 0x46,
+	0x91, 0x92, 0xab, 0x96, 0xdc, 0xa1, 0xef, 0xa0, 0x42, 0xfc, 0x20, 0xec, 0x9f, 0xdf, 0x18, 0x1a,
+	0x65, 0x34, 0xbb, 0x9f, 0x76, 0x66, 0xc5, 0xa9, 0xc3, 0x2c, 0x9d, 0x52, 0xc1, 0x0e, 0xfb, 0x78,
+	0x7e, 0x63, 0x95, 0x09, 0xff, 0x66, 0x7a, 0x47, 0x8e, 0x1b, 0xe2, 0xc0, 0x28, 0x09, 0xbd, 0x62,
+	0x87, 0xf6, 0x01, 0xb8, 0x5e, 0x3f, 0x18, 0x52, 0xde, 0x02, 0x57, 0xbd, 0x95, 0x43, 0xf5, 0x09,
+	0x93, 0xb7, 0xaa, 0x44, 0x2d, 0xd1, 0xb7, 0x50, 0x17, 0x21, 0xe9, 0x0f, 0xfc, 0x21, 0x26, 0x46,
+	0xf9, 0x91, 0x46, 0x55, 0x6d, 0x08, 0x55, 0x2a, 0xfc, 0xa7, 0x22, 0x68, 0xbb, 0x54, 0xc2, 0xaa,
+	0x09, 0x71, 0xb6, 0x26, 0xe8, 0x21, 0x54, 0x3d, 0xfb, 0x0a, 0x93, 0xb1, 0x3d, 0xc0, 0x46, 0x85,
+	0x7b, 0x78, 0x4b, 0x60, 0xa1, 0xe2, 0x08, 0x1b, 0x3a, 0xe7, 0x88, 0x0d, 0xda, 0x84, 0x45, 0xc7,
+	0x1b, 0xb8, 0x93, 0x21, 0xee, 0x0f, 0xa9, 0xfe, 0x10, 0x0f, 0x8d, 0x2a, 0xe5, 0xeb, 0x56, 0x53,
+	0x92, 0xf7, 0x04, 0xd5, 0xfc, 0x15, 0x74, 0xe5, 0xbb, 0xd9, 0x85, 0xb2, 0x88, 0x0c, 0xaa, 0x41,
+	0xe5, 0xec, 0xf8, 0xfb, 0xe3, 0x93, 0x1f, 0x8f, 0x5b, 0x0f, 0x90, 0x0e, 0xa5, 0xe3, 0x9d, 0x1f,
+	0x7a, 0xad, 0x02, 0x5a, 0x82, 0xc6, 0xd1, 0xce, 0xe9, 0xab, 0xbe, 0xd5, 0x3b, 0xea, 0xed, 0x9c,
+	0xf6, 0xf6, 0x5a, 0x45, 0xf3, 0x23, 0xa8, 0x46, 0x57, 0x46, 0x15, 0xd0, 0x76, 0x4e, 0x77, 0xc5,
+	0x91, 0xbd, 0x1e, 0x5d, 0x15, 0xcc, 0x3f, 0x0a, 0xb0, 0x92, 0x44, 0x98, 0x8c, 0x7d, 0x8f, 0x08,
+	0xbf, 0xfd, 0x89, 0x17, 0x41, 0xcc, 0x37, 0x08, 0x41, 0xc9, 0xc3, 0xef, 0x14, 0xc0, 0x7c, 0xcd,
+	0x24, 0x43, 0x3f, 0xb4, 0x5d, 0x0e, 0x2e, 0x95, 0xe4, 0x1b, 0xf4, 0x25, 0xe8, 0x32, 0x72, 0x84,
+	0xc2, 0xa6, 0x6d, 0xd5, 0xba, 0xab, 0xc9, 0x78, 0x4a, 0x8b, 0x56, 0x24, 0x66, 0xee, 0xc3, 0xfa,
+	0x3e, 0x56, 0x9e, 0x88, 0x70, 0xab, 0x84, 0x63, 0x76, 0x69, 0x48, 0xb9, 0x33, 0xcc, 0x2e, 0x5d,
+	0x23, 0x03, 0x2a, 0x32, 0x5b, 0xb9, 0x3b, 0x0b, 0x96, 0xda, 0x9a, 0x21, 0x18, 0xd3, 0x8a, 0xe4,
+	0xbd, 0x66, 0x69, 0x7a, 0x0c, 0x25, 0x56, 0x48, 0x5c, 0x4d, 0xad, 0x8b, 0x92, 0x7e, 0x1e, 0x52,
+	0x8e, 0xc5, 0xf9, 0x49, 0xa4, 0xb5, 0x14, 0xd2, 0xe6, 0x41, 0xdc, 0xea, 0xae, 0xef, 0x85, 0xd8,
+	0x0b, 0xef, 0xe7, 0xff, 0x11, 0x6c, 0xcc, 0xd0, 0x24, 0x2f, 0xb0, 0x0d, 0x15, 0xe9, 0x1a, 0xd7,
+	0x96, 0x19, 0x57, 0x25, 0x65, 0xfe, 0xad, 0xc1, 0xca, 0xd9, 0x78, 0x68, 0x87, 0x58, 0xb1, 0xe6,
+	0x38, 0xb5, 0xa9, 0xd2, 0x55, 0xc4, 0x62, 0x49, 0xe8, 0x16, 0x5d, 0x6b, 0x97, 0x7d, 0xaa, 0x0c,
+	0x7e, 0x0a, 0xe5, 0x6b, 0xdb, 0xa5, 0x7a, 0x78, 0x20, 0xa2, 0xa8, 0x49, 0x49, 0xde, 0xcd, 0x2c,
+	0x29, 0x81, 0xd6, 0xa1, 0x32, 0x0c, 0x6e, 0x58, 0x3b, 0xe2, 0x15, 0xac, 0x5b, 0x65, 0xba, 0xb5,
+	0x26, 0x1e, 0xfa, 0x04, 0x1a, 0x43, 0x87, 0xd8, 0xe7, 0x2e, 0xee, 0x5f, 0xfa, 0xfe, 0x5b, 0xc2,
+	0x8b, 0x58, 0xb7, 0xea, 0x92, 0x78, 0xc0, 0x68, 0xa8, 0xcd, 0x32, 0x69, 0x10, 0x60, 0x7a, 0x01,
+	0x5a, 0x99, 0x8c, 0x1f, 0xed, 0x59, 0x0c, 0x43, 0xe7, 0x0a, 0xfb, 0x93, 0x90, 0x57, 0x9e, 0x66,
+	0xa9, 0x2d, 0xfa, 0x18, 0xea, 0x01, 0xa6, 0xdd, 0xa7, 0x2f, 0xbd, 0xd4, 0xf9, 0xc9, 0x1a, 0xa7,
+	0xbd, 0x16, 0x6e, 0xd1, 0xfb, 0xff, 0x6e, 0xd3, 0x26, 0x26, 0x2a, 0x8f, 0xaf, 0xc5, 0xb1, 0x09,
+	0xc1, 0xea, 0x18, 0xa8, 0x63, 0x94, 0x26, 0x8f, 0xd1, 0x7c, 0x1f, 0xf9, 0x01, 0xcd, 0x80, 0x1a,
+	0xe7, 0x89, 0x0d, 0x7a, 0x04, 0x35, 0xda, 0x0c, 0x06, 0x81, 0x33, 0x0e, 0x19, 0xa2, 0x75, 0x1e,
+	0xd3, 0x38, 0x09, 0x99, 0xd0, 0x60, 0x26, 0xfa, 0x54, 0xbe, 0xff, 0xc6, 0x3f, 0x27, 0x46, 0x43,
+	0xe8, 0x66, 0xc4, 0x17, 0x7e, 0xf0, 0x92, 0x92, 0x68, 0x0e, 0xad, 0xa6, 0xa0, 0xba, 0x2f, 0xea,
+	0xff, 0x14, 0x61, 0xcd, 0xf2, 0x5d, 0xf7, 0xdc, 0x1e, 0xbc, 0xcd, 0x81, 0x7b, 0x0c, 0xa2, 0xe2,
+	0x7c, 0x88, 0xb4, 0x19, 0x10, 0xc5, 0x52, 0xb9, 0x94, 0x48, 0xe5, 0x04, 0x78, 0x0b, 0xd9, 0xe0,
+	0x95, 0x93, 0xe0, 0x29, 0x64, 0x2a, 0x31, 0x64, 0xa2, 0xb0, 0xeb, 0x73, 0xc2, 0x5e, 0x9d, 0x0e,
+	0xfb, 0x63, 0x58, 0x1c, 0xd0, 0xeb, 0x7b, 0x93, 0x71, 0xdf, 0xf7, 0xfa, 0x23, 0xdb, 0x71, 0x25,
+	0xa8, 0x0d, 0x49, 0x3e, 0xf1, 0x5e, 0x50, 0xe2, 0x34, 0x3c, 0xb5, 0x69, 0x78, 0x5e, 0xc2, 0xfa,
+	0x54, 0x4c, 0xef, 0x0b, 0xd0, 0x9f, 0x1a, 0xac, 0x1e, 0x7a, 0xf4, 0x21, 0x71, 0xdd, 0x14, 0x3e,
+	0x51, 0x0d, 0x16, 0x72, 0xd7, 0x60, 0xf1, 0x7d, 0x6a, 0x50, 0x4b, 0x00, 0xac, 0xb2, 0xa1, 0x14,
+	0xcb, 0x86, 0x5c, 0x75, 0x99, 0xe8, 0x86, 0xe5, 0xf4, 0xbb, 0xf7, 0x21, 0x80, 0x28, 0x24, 0xae,
+	0x5c, 0x00, 0x59, 0xe5, 0x94, 0x63, 0xd9, 0xfc, 0x14, 0xf6, 0xfa, 0x6c, 0xec, 0xe3, 0x55, 0xb9,
+	0x05, 0x2d, 0xe5, 0xcf, 0x20, 0x18, 0x72, 0x9f, 0x24, 0x88, 0x4d, 0x49, 0xdf, 0x0d, 0x86, 0xcc,
+	0xab, 0x74, 0x3e, 0xd4, 0x72, 0x94, 0x61, 0x7d, 0x1a, 0xe7, 0x43, 0x58, 0x4b, 0x43, 0x73, 0x5f,
+	0x98, 0xff, 0x2a, 0xc0, 0xfa, 0x99, 0xe7, 0xcc, 0x04, 0x7a, 0x56, 0x21, 0x4e, 0x85, 0xbe, 0x38,
+	0x23, 0xf4, 0xb4, 0x16, 0xc6, 0x93, 0xe0, 0x02, 0x4b, 0x28, 0xc5, 0x26, 0x1e, 0xd3, 0x52, 0x32,
+	0xa6, 0xa9, 0xa8, 0x2c, 0x4c, 0x45, 0xc5, 0xec, 0x83, 0x31, 0xed, 0xe5, 0x3d, 0xef, 0xcc, 0xee,
+	0x15, 0xbd, 0xa7, 0x55, 0xf1, 0x76, 0x9a, 0xcb, 0xb0, 0x44, 0xdf, 0xb4, 0xd7, 0xa2, 0x2d, 0xc8,
+	0x00, 0x98, 0x3d, 0x40, 0x71, 0xe2, 0xad, 0x3d, 0x49, 0x4a, 0xda, 0x53, 0xb3, 0xa9, 0x92, 0x57,
+	0x52, 0xe6, 0x37, 0x5c, 0xf7, 0x01, 0x1d, 0x63, 0x7c, 0x9a, 0xd3, 0x73, 0x82, 0xdb, 0x02, 0xed,
+	0xca, 0x7e, 0x27, 0x9f, 0x5b, 0xb6, 0xa4, 0x33, 0x07, 0x8a, 0x1f, 0x95, 0x1e, 0xc4, 0x87, 0x97,
+	0x42, 0xbe, 0xe1, 0xe5, 0x67, 0x40, 0xaf, 0x70, 0x34, 0x47, 0xdd, 0xf1, 0xee, 0x2b, 0x98, 0x8a,
+	0x49, 0x98, 0x28, 0x47, 0xf6, 0x24, 0x09, 0xac, 0xda, 0x9a, 0xbf, 0xc0, 0x72, 0x42, 0xbb, 0xf4,
+	0x93, 0xdd, 0x87, 0x5c, 0x48, 0xed, 0x6c, 0x89, 0xbe, 0x86, 0xb2, 0x98, 0x4d, 0xb9, 0xee, 0x66,
+	0xf7, 0x61, 0xd2, 0x6f, 0xae, 0x84, 0xfe, 0x2a, 0x90, 0x43, 0x91, 0x94, 0xed, 0xfe, 0xab, 0x43,
+	0x53, 0x8d, 0x4b, 0x62, 0x72, 0x46, 0x0e, 0xd4, 0xe3, 0x73, 0x21, 0x7a, 0x92, 0x3d, 0x58, 0xa7,
+	0x7e, 0x1d, 0xb4, 0x9f, 0xe6, 0x11, 0x15, 0x37, 0x30, 0x1f, 0x7c, 0x51, 0x40, 0x04, 0x5a, 0xe9,
+	0x71, 0x0d, 0x3d, 0x9b, 0xad, 0x23, 0x63, 0x3e, 0x6c, 0x77, 0xf2, 0x8a, 0x2b, 0xb3, 0xe8, 0x9a,
+	0xe7, 0x4c, 0x72, 0xc6, 0x42, 0x77, 0xaa, 0x49, 0x8e, 0x75, 0xed, 0xed, 0xdc, 0xf2, 0x91, 0xdd,
+	0x37, 0xd0, 0x48, 0xbc, 0xf0, 0x28, 0x23, 0x5a, 0xb3, 0x26, 0xb6, 0xf6, 0x67, 0xb9, 0x64, 0x23,
+	0x5b, 0x57, 0xd0, 0x4c, 0xb6, 0x31, 0x94, 0xa1, 0x60, 0xe6, 0x3b, 0xd4, 0xfe, 0x3c, 0x9f, 0x70,
+	0x64, 0x8e, 0xe2, 0x98, 0xee, 0x21, 0x59, 0x38, 0x66, 0x74, 0xc4, 0x2c, 0x1c, 0xb3, 0x5a, 0x13,
+	0x35, 0x6a, 0x03, 0xdc, 0xb6, 0x10, 0xb4, 0x99, 0x09, 0x48, 0xb2, 0xf3, 0xb4, 0xb7, 0xee, 0x16,
+	0x8c, 0x4c, 0x8c, 0x61, 0x31, 0xf5, 0xea, 0xa3, 0x8c, 0xd0, 0xcc, 0x1e, 0xb8, 0xda, 0xcf, 0x72,
+	0x4a, 0xa7, 0x2e, 0x25, 0xbb, 0xd2, 0x9c, 0x4b, 0x25, 0x5b, 0xde, 0x9c, 0x4b, 0xa5, 0x1a, 0x1c,
+	0x35, 0xe1, 0xd0, 0x8a, 0x9f, 0x78, 0xd2, 0x34, 0x6b, 0x0b, 0x28, 0xe3, 0xf4, 0x74, 0x57, 0x6b,
+	0x3f, 0xc9, 0x21, 0x79, 0x5b, 0xdf, 0xcf, 0xe1, 0x27, 0x5d, 0x89, 0x9e, 0x97, 0xf9, 0x1f, 0x0b,
+	0x5f, 0xfd, 0x0f, 0x21, 0x04, 0xa4, 0x21, 0x46, 0x11, 0x00, 0x00,
 }
@@ -143,7 +143,7 @@ func (t *test) assignTestResult(podStatus core.PodPhase) error {
 func expectedSuccess(hookTypes []string) (bool, error) {
 	for _, hookType := range hookTypes {
 		hookType = strings.ToLower(strings.TrimSpace(hookType))
-		if hookType == hooks.ReleaseTestSuccess {
+		if hookType == hooks.ReleaseTestSuccess || hookType == hooks.ReleaseTest {
 			return true, nil
 		} else if hookType == hooks.ReleaseTestFailure {
 			return false, nil
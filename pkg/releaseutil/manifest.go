@@ -28,30 +28,36 @@ type SimpleHead struct {
 	Kind     string `json:"kind,omitempty"`
 	Metadata *struct {
 		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace,omitempty"`
 		Annotations map[string]string `json:"annotations"`
 	} `json:"metadata,omitempty"`
 }
 
-var sep = regexp.MustCompile("(?:^|\\s*\n)---\\s*")
+var sep = regexp.MustCompile("(?:^|\\s*\n)---[ \\t]*")
 
 // SplitManifests takes a string of manifest and returns a map contains individual manifests
+//
+// A leading UTF-8 byte order mark is dropped, CRLF line endings are converted to
+// LF and documents that are empty, such as those around leading or trailing
+// separators, are skipped.
 func SplitManifests(bigFile string) map[string]string {
 	// Basically, we're quickly splitting a stream of YAML documents into an
 	// array of YAML docs. In the current implementation, the file name is just
 	// a place holder, and doesn't have any further meaning.
 	tpl := "manifest-%d"
 	res := map[string]string{}
+	bigFileTmp := strings.TrimPrefix(bigFile, "\ufeff")
+	bigFileTmp = strings.Replace(bigFileTmp, "\r\n", "\n", -1)
 	// Making sure that any extra whitespace in YAML stream doesn't interfere in splitting documents correctly.
-	bigFileTmp := strings.TrimSpace(bigFile)
+	bigFileTmp = strings.TrimSpace(bigFileTmp)
 	docs := sep.Split(bigFileTmp, -1)
 	var count int
 	for _, d := range docs {
-
+		d = strings.TrimSpace(d)
 		if d == "" {
 			continue
 		}
 
-		d = strings.TrimSpace(d)
 		res[fmt.Sprintf(tpl, count)] = d
 		count = count + 1
 	}
@@ -59,3 +59,49 @@ func TestSplitManifest(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expected, manifests)
 	}
 }
+
+func TestSplitManifestEdgeCases(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect map[string]string
+	}{
+		{
+			name:   "byte order mark",
+			input:  "\ufeffapiVersion: v1\nkind: Pod\n",
+			expect: map[string]string{"manifest-0": "apiVersion: v1\nkind: Pod"},
+		},
+		{
+			name:   "byte order mark before separator",
+			input:  "\ufeff---\napiVersion: v1\nkind: Pod\n",
+			expect: map[string]string{"manifest-0": "apiVersion: v1\nkind: Pod"},
+		},
+		{
+			name:  "leading and trailing separators",
+			input: "---\nkind: Pod\n---\n  \n---\nkind: Service\n---\n",
+			expect: map[string]string{
+				"manifest-0": "kind: Pod",
+				"manifest-1": "kind: Service",
+			},
+		},
+		{
+			name:  "CRLF line endings",
+			input: "---\r\nkind: Pod\r\nmetadata:\r\n  name: a\r\n---\r\nkind: Service\r\n",
+			expect: map[string]string{
+				"manifest-0": "kind: Pod\nmetadata:\n  name: a",
+				"manifest-1": "kind: Service",
+			},
+		},
+		{
+			name:   "only separators",
+			input:  "---\n---\n",
+			expect: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := SplitManifests(tt.input); !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expect, got)
+		}
+	}
+}
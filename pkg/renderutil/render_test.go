@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package renderutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestRender(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello", Version: "0.1.0"},
+		Templates: []*chart.Template{
+			{Name: "templates/hello", Data: []byte("hello: {{ .Values.who }} in {{ .Release.Namespace }} on {{ .Capabilities.KubeVersion.Minor }}")},
+		},
+		Values: &chart.Config{Raw: "who: world"},
+	}
+	opts := Options{
+		ReleaseOptions: chartutil.ReleaseOptions{Name: "test", Namespace: "ns"},
+		KubeVersion:    "1.6",
+	}
+
+	out, err := Render(c, &chart.Config{Raw: "{}"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := "hello: world in ns on 6"
+	if out["hello/templates/hello"] != expect {
+		t.Errorf("Expected %q, got %q", expect, out["hello/templates/hello"])
+	}
+	if chartutil.DefaultKubeVersion.Minor != "9" {
+		t.Errorf("Expected the default kube version to be left alone, got %s", chartutil.DefaultKubeVersion.Minor)
+	}
+
+	if _, err := Render(c, nil, Options{KubeVersion: "nope"}); err == nil {
+		t.Error("Expected an error for an invalid kube version")
+	}
+}
+
+func TestRenderMissingDependencies(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "parent", Version: "0.1.0"},
+		Files: []*any.Any{
+			{TypeUrl: "requirements.yaml", Value: []byte("dependencies:\n- name: child\n  version: 0.1.0\n")},
+		},
+	}
+
+	_, err := Render(c, &chart.Config{Raw: "{}"}, Options{})
+	if err == nil || !strings.Contains(err.Error(), "missing in charts/ directory: child") {
+		t.Errorf("Expected a missing dependency error, got %v", err)
+	}
+}
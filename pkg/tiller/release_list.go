@@ -33,6 +33,9 @@ func (s *ReleaseServer) ListReleases(req *services.ListReleasesRequest, stream s
 
 	//rels, err := s.env.Releases.ListDeployed()
 	rels, err := s.env.Releases.ListFilterAll(func(r *release.Release) bool {
+		if req.IncludeDeleted && r.Info.Status.Code == release.Status_DELETED {
+			return true
+		}
 		for _, sc := range req.StatusCodes {
 			if sc == r.Info.Status.Code {
 				return true
@@ -51,6 +54,10 @@ func (s *ReleaseServer) ListReleases(req *services.ListReleasesRequest, stream s
 		}
 	}
 
+	if req.Chart != "" {
+		rels = filterByChart(req.Chart, rels)
+	}
+
 	if len(req.Filter) != 0 {
 		rels, err = filterReleases(req.Filter, rels)
 		if err != nil {
@@ -163,6 +170,16 @@ func filterByNamespace(namespace string, rels []*release.Release) ([]*release.Re
 	return matches, nil
 }
 
+func filterByChart(name string, rels []*release.Release) []*release.Release {
+	matches := []*release.Release{}
+	for _, r := range rels {
+		if r.GetChart().GetMetadata().GetName() == name {
+			matches = append(matches, r)
+		}
+	}
+	return matches
+}
+
 func filterReleases(filter string, rels []*release.Release) ([]*release.Release, error) {
 	preg, err := regexp.Compile(filter)
 	if err != nil {
@@ -231,3 +231,67 @@ func TestReleasesNamespace(t *testing.T) {
 		t.Errorf("Expected 2 releases, got %d", len(mrs.val.Releases))
 	}
 }
+
+func TestReleasesChart(t *testing.T) {
+	rs := rsFixture()
+
+	for _, name := range []string{"axon", "dendrite"} {
+		rel := releaseStub()
+		rel.Name = name
+		if err := rs.env.Releases.Create(rel); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+	rel := releaseWithKeepStub("neuron")
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	mrs := &mockListServer{}
+	req := &services.ListReleasesRequest{
+		Limit: 64,
+		Chart: "bunnychart",
+	}
+
+	if err := rs.ListReleases(req, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+
+	if len(mrs.val.Releases) != 1 || mrs.val.Releases[0].Name != "neuron" {
+		t.Errorf("Expected only neuron, got %v", mrs.val.Releases)
+	}
+}
+
+func TestListReleasesIncludeDeleted(t *testing.T) {
+	rs := rsFixture()
+
+	for name, code := range map[string]release.Status_Code{
+		"axon":     release.Status_DEPLOYED,
+		"dendrite": release.Status_DELETED,
+		"neuron":   release.Status_FAILED,
+	} {
+		rel := namedReleaseStub(name, code)
+		if err := rs.env.Releases.Create(rel); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+
+	mrs := &mockListServer{}
+	req := &services.ListReleasesRequest{
+		Limit:          64,
+		SortBy:         services.ListSort_NAME,
+		IncludeDeleted: true,
+	}
+
+	if err := rs.ListReleases(req, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+
+	names := []string{}
+	for _, rel := range mrs.val.Releases {
+		names = append(names, rel.Name)
+	}
+	if len(names) != 2 || names[0] != "axon" || names[1] != "dendrite" {
+		t.Errorf("Expected axon and dendrite, got %v", names)
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/manifest"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	rudderAPI "k8s.io/helm/pkg/proto/hapi/rudder"
 	"k8s.io/helm/pkg/proto/hapi/services"
@@ -52,21 +53,38 @@ type LocalReleaseModule struct {
 // Create creates a release via kubeclient from provided environment
 func (m *LocalReleaseModule) Create(r *release.Release, req *services.InstallReleaseRequest, env *environment.Environment) error {
 	b := bytes.NewBufferString(r.Manifest)
-	return env.KubeClient.Create(r.Namespace, b, req.Timeout, req.Wait)
+	return env.KubeClient.CreateWithOptions(r.Namespace, b, kube.CreateOptions{
+		Timeout:     req.Timeout,
+		ShouldWait:  req.Wait,
+		WaitForJobs: req.WaitForJobs,
+	})
 }
 
 // Update performs an update from current to target release
 func (m *LocalReleaseModule) Update(current, target *release.Release, req *services.UpdateReleaseRequest, env *environment.Environment) error {
 	c := bytes.NewBufferString(current.Manifest)
 	t := bytes.NewBufferString(target.Manifest)
-	return env.KubeClient.Update(target.Namespace, c, t, req.Force, req.Recreate, req.Timeout, req.Wait)
+	return env.KubeClient.UpdateWithOptions(target.Namespace, c, t, kube.UpdateOptions{
+		Force:       req.Force,
+		Recreate:    req.Recreate,
+		Timeout:     req.Timeout,
+		ShouldWait:  req.Wait,
+		WaitForJobs: req.WaitForJobs,
+	})
 }
 
 // Rollback performs a rollback from current to target release
 func (m *LocalReleaseModule) Rollback(current, target *release.Release, req *services.RollbackReleaseRequest, env *environment.Environment) error {
 	c := bytes.NewBufferString(current.Manifest)
 	t := bytes.NewBufferString(target.Manifest)
-	return env.KubeClient.Update(target.Namespace, c, t, req.Force, req.Recreate, req.Timeout, req.Wait)
+	return env.KubeClient.UpdateWithOptions(target.Namespace, c, t, kube.UpdateOptions{
+		Force:         req.Force,
+		Recreate:      req.Recreate,
+		Timeout:       req.Timeout,
+		ShouldWait:    req.Wait,
+		WaitForJobs:   req.WaitForJobs,
+		CleanupOnFail: req.CleanupOnFail,
+	})
 }
 
 // Status returns kubectl-like formatted status of release objects
@@ -86,8 +104,18 @@ func (m *LocalReleaseModule) Delete(rel *release.Release, req *services.Uninstal
 // RemoteReleaseModule is a ReleaseModule which calls Rudder service to operate on a release
 type RemoteReleaseModule struct{}
 
+// errRudderWaitForJobs and errRudderCleanupOnFail are returned for options the
+// Rudder API has no field for, instead of silently ignoring them.
+var (
+	errRudderWaitForJobs   = errors.New("waiting for jobs is not supported with Rudder")
+	errRudderCleanupOnFail = errors.New("cleaning up on failure is not supported with Rudder")
+)
+
 // Create calls rudder.InstallRelease
 func (m *RemoteReleaseModule) Create(r *release.Release, req *services.InstallReleaseRequest, env *environment.Environment) error {
+	if req.WaitForJobs {
+		return errRudderWaitForJobs
+	}
 	request := &rudderAPI.InstallReleaseRequest{Release: r}
 	_, err := rudder.InstallRelease(request)
 	return err
@@ -95,6 +123,9 @@ func (m *RemoteReleaseModule) Create(r *release.Release, req *services.InstallRe
 
 // Update calls rudder.UpgradeRelease
 func (m *RemoteReleaseModule) Update(current, target *release.Release, req *services.UpdateReleaseRequest, env *environment.Environment) error {
+	if req.WaitForJobs {
+		return errRudderWaitForJobs
+	}
 	upgrade := &rudderAPI.UpgradeReleaseRequest{
 		Current:  current,
 		Target:   target,
@@ -109,6 +140,12 @@ func (m *RemoteReleaseModule) Update(current, target *release.Release, req *serv
 
 // Rollback calls rudder.Rollback
 func (m *RemoteReleaseModule) Rollback(current, target *release.Release, req *services.RollbackReleaseRequest, env *environment.Environment) error {
+	if req.WaitForJobs {
+		return errRudderWaitForJobs
+	}
+	if req.CleanupOnFail {
+		return errRudderCleanupOnFail
+	}
 	rollback := &rudderAPI.RollbackReleaseRequest{
 		Current:  current,
 		Target:   target,
@@ -150,7 +187,7 @@ func (m *RemoteReleaseModule) Delete(r *release.Release, req *services.Uninstall
 // DeleteRelease is a helper that allows Rudder to delete a release without exposing most of Tiller inner functions
 func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient environment.KubeClient) (kept string, errs []error) {
 	manifests := relutil.SplitManifests(rel.Manifest)
-	_, files, err := sortManifests(manifests, vs, UninstallOrder)
+	_, files, _, err := manifest.Partition(manifests, vs, manifest.UninstallOrder)
 	if err != nil {
 		// We could instead just delete everything in no particular order.
 		// FIXME: One way to delete at this point would be to try a label-based
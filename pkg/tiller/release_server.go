@@ -20,7 +20,6 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"path"
 	"regexp"
 	"strings"
 
@@ -32,6 +31,7 @@ import (
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/manifest"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
@@ -284,27 +284,14 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 		return nil, nil, "", err
 	}
 
-	// NOTES.txt gets rendered like all the other files, but because it's not a hook nor a resource,
-	// pull it out of here into a separate file so that we can actually use the output of the rendered
-	// text file. We have to spin through this map because the file contains path information, so we
-	// look for terminating NOTES.txt. We also remove it from the files so that we don't have to skip
-	// it in the sortHooks.
-	notes := ""
-	for k, v := range files {
-		if strings.HasSuffix(k, notesFileSuffix) {
-			// Only apply the notes if it belongs to the parent chart
-			// Note: Do not use filePath.Join since it creates a path with \ which is not expected
-			if k == path.Join(ch.Metadata.Name, "templates", notesFileSuffix) {
-				notes = v
-			}
-			delete(files, k)
-		}
-	}
-
 	// Sort hooks, manifests, and partials. Only hooks and manifests are returned,
 	// as partials are not used after renderer.Render. Empty manifests are also
 	// removed here.
-	hooks, manifests, err := sortManifests(files, vs, InstallOrder)
+	//
+	// NOTES.txt gets rendered like all the other files, but because it's not a hook nor a resource,
+	// it is returned separately so that we can actually use the output of the rendered
+	// text file. Only the notes of the parent chart are applied.
+	hooks, manifests, allNotes, err := manifest.Partition(files, vs, manifest.InstallOrder)
 	if err != nil {
 		// By catching parse errors here, we can prevent bogus releases from going
 		// to Kubernetes.
@@ -313,7 +300,7 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 		// errors.
 		b := bytes.NewBuffer(nil)
 		for name, content := range files {
-			if len(strings.TrimSpace(content)) == 0 {
+			if len(strings.TrimSpace(content)) == 0 || strings.HasSuffix(name, notesFileSuffix) {
 				continue
 			}
 			b.WriteString("\n---\n# Source: " + name + "\n")
@@ -329,7 +316,8 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 		b.WriteString(m.Content)
 	}
 
-	return hooks, b, notes, nil
+	// The parent chart renders to <name>/templates, so its notes are keyed by its name
+	return hooks, b, allNotes[ch.Metadata.Name], nil
 }
 
 // recordRelease with an update operation in case reuse has been set.
@@ -351,16 +339,7 @@ func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 	}
 
 	s.Log("executing %d %s hooks for %s", len(hs), hook, name)
-	executingHooks := []*release.Hook{}
-	for _, h := range hs {
-		for _, e := range h.Events {
-			if e == code {
-				executingHooks = append(executingHooks, h)
-			}
-		}
-	}
-
-	executingHooks = sortByHookWeight(executingHooks)
+	executingHooks := manifest.HookPlan(hs)[code]
 
 	for _, h := range executingHooks {
 		if err := s.deleteHookByPolicy(h, hooks.BeforeHookCreation, name, namespace, hook, kubeCli); err != nil {
@@ -378,7 +357,11 @@ func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 
 		// We can't watch CRDs
 		if hook != hooks.CRDInstall {
-			if err := kubeCli.WatchUntilReady(namespace, b, timeout, false); err != nil {
+			hookTimeout := timeout
+			if h.Timeout > 0 {
+				hookTimeout = h.Timeout
+			}
+			if err := kubeCli.WatchUntilReady(namespace, b, hookTimeout, false); err != nil {
 				s.Log("warning: Release %s %s %s could not complete: %s", name, hook, h.Path, err)
 				// If a hook is failed, checkout the annotation of the hook to determine whether the hook should be deleted
 				// under failed condition. If so, then clear the corresponding resource object in the hook
@@ -436,12 +419,6 @@ func (s *ReleaseServer) deleteHookByPolicy(h *release.Hook, policy string, name,
 // hookShouldBeDeleted determines whether the defined hook deletion policy matches the hook deletion polices
 // supported by helm. If so, mark the hook as one should be deleted.
 func hookHasDeletePolicy(h *release.Hook, policy string) bool {
-	if dp, ok := deletePolices[policy]; ok {
-		for _, v := range h.DeletePolicies {
-			if dp == v {
-				return true
-			}
-		}
-	}
-	return false
+	dp, ok := deletePolices[policy]
+	return ok && hooks.HasDeletePolicy(h, dp)
 }
@@ -344,6 +344,24 @@ func TestGetVersionSet(t *testing.T) {
 	}
 }
 
+func TestRemoteReleaseModuleUnsupportedOptions(t *testing.T) {
+	m := &RemoteReleaseModule{}
+	rel := releaseStub()
+
+	if err := m.Create(rel, &services.InstallReleaseRequest{WaitForJobs: true}, nil); err != errRudderWaitForJobs {
+		t.Errorf("expected %v installing, got %v", errRudderWaitForJobs, err)
+	}
+	if err := m.Update(rel, rel, &services.UpdateReleaseRequest{WaitForJobs: true}, nil); err != errRudderWaitForJobs {
+		t.Errorf("expected %v upgrading, got %v", errRudderWaitForJobs, err)
+	}
+	if err := m.Rollback(rel, rel, &services.RollbackReleaseRequest{WaitForJobs: true}, nil); err != errRudderWaitForJobs {
+		t.Errorf("expected %v rolling back, got %v", errRudderWaitForJobs, err)
+	}
+	if err := m.Rollback(rel, rel, &services.RollbackReleaseRequest{CleanupOnFail: true}, nil); err != errRudderCleanupOnFail {
+		t.Errorf("expected %v rolling back, got %v", errRudderCleanupOnFail, err)
+	}
+}
+
 func TestUniqName(t *testing.T) {
 	rs := rsFixture()
 
@@ -435,6 +453,10 @@ func (u *updateFailingKubeClient) Update(namespace string, originalReader, modif
 	return errors.New("Failed update in kube client")
 }
 
+func (u *updateFailingKubeClient) UpdateWithOptions(namespace string, originalReader, modifiedReader io.Reader, opts kube.UpdateOptions) error {
+	return errors.New("Failed update in kube client")
+}
+
 func newHookFailingKubeClient() *hookFailingKubeClient {
 	return &hookFailingKubeClient{
 		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
@@ -517,6 +539,9 @@ func (kc *mockHooksKubeClient) Create(ns string, r io.Reader, timeout int64, sho
 
 	return nil
 }
+func (kc *mockHooksKubeClient) CreateWithOptions(ns string, r io.Reader, opts kube.CreateOptions) error {
+	return kc.Create(ns, r, opts.Timeout, opts.ShouldWait)
+}
 func (kc *mockHooksKubeClient) Get(ns string, r io.Reader) (string, error) {
 	return "", nil
 }
@@ -550,6 +575,9 @@ func (kc *mockHooksKubeClient) WatchUntilReady(ns string, r io.Reader, timeout i
 func (kc *mockHooksKubeClient) Update(ns string, currentReader, modifiedReader io.Reader, force bool, recreate bool, timeout int64, shouldWait bool) error {
 	return nil
 }
+func (kc *mockHooksKubeClient) UpdateWithOptions(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) error {
+	return nil
+}
 func (kc *mockHooksKubeClient) Build(ns string, reader io.Reader) (kube.Result, error) {
 	return []*resource.Info{}, nil
 }